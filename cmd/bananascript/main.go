@@ -2,6 +2,7 @@ package main
 
 import (
 	"bananascript/src/builtins"
+	"bananascript/src/errors"
 	"bananascript/src/evaluator"
 	"bananascript/src/lexer"
 	"bananascript/src/parser"
@@ -43,22 +44,24 @@ func runFile(fileName string) {
 	theParser := parser.New(theLexer)
 	context, environment := builtins.NewContextAndEnvironment()
 
-	program, errors := theParser.ParseProgram(context)
-	if len(errors) > 0 {
+	program, parserErrors := theParser.ParseProgram(context)
+	if len(parserErrors) > 0 {
 		errorStr := "Encountered %d error"
-		if len(errors) > 1 {
+		if len(parserErrors) > 1 {
 			errorStr += "s"
 		}
 		errorStr += ":"
-		fmt.Println(color.FgRed.Sprintf(errorStr, len(errors)))
-		for _, err := range errors {
+		fmt.Println(color.FgRed.Sprintf(errorStr, len(parserErrors)))
+		for _, err := range parserErrors {
 			fmt.Println(err.PrettyPrint(true))
 		}
 		os.Exit(1)
 	} else {
 		object := evaluator.Eval(program, environment)
 		if err, isError := object.(*evaluator.ErrorObject); isError {
-			fmt.Println(err.Message)
+			uncaught := errors.New(err.Line, err.Col, err.File, "Uncaught %s: %s", err.Kind, err.Message)
+			fmt.Println(uncaught.PrettyPrint(true))
+			os.Exit(1)
 		}
 	}
 }
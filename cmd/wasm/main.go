@@ -7,6 +7,7 @@ import (
 	"bananascript/src/evaluator"
 	"bananascript/src/lexer"
 	"bananascript/src/parser"
+	"fmt"
 	"strings"
 	"syscall/js"
 )
@@ -52,9 +53,10 @@ func run(_ js.Value, args []js.Value) interface{} {
 	if errObj, isError := obj.(*evaluator.ErrorObject); isError {
 		return result(output.String(), []interface{}{
 			map[string]interface{}{
-				"message": errObj.Message,
-				"line":    0,
-				"col":     0,
+				"message": fmt.Sprintf("Uncaught %s: %s", errObj.Kind, errObj.Message),
+				"kind":    errObj.Kind,
+				"line":    errObj.Line,
+				"col":     errObj.Col,
 			},
 		})
 	}
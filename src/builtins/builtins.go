@@ -39,8 +39,18 @@ var anyBuiltin = &types.Iface{
 	Members: make(map[string]types.Type),
 }
 
+var elementTypeParameter = &types.TypeParameter{Name: "T"}
+var mappedTypeParameter = &types.TypeParameter{Name: "U"}
+
+var keyTypeParameter = &types.TypeParameter{Name: "K"}
+var valueTypeParameter = &types.TypeParameter{Name: "V"}
+
+var arrayBuiltin = &types.Array{ElementType: elementTypeParameter}
+var mapBuiltin = &types.Map{KeyType: keyTypeParameter, ValueType: valueTypeParameter}
+
 var builtinTypes = map[string]types.Type{
-	"any": anyBuiltin,
+	"any":   anyBuiltin,
+	"error": types.Error,
 }
 
 func makeBuiltinObjects(printFn func(string), promptFn func(string) string) map[types.Type]map[string]evaluator.Object {
@@ -49,20 +59,22 @@ func makeBuiltinObjects(printFn func(string), promptFn func(string) string) map[
 			"println": &BuiltinFunction{
 				FunctionType: &types.Function{
 					ParameterTypes: []types.Type{anyBuiltin},
+					Variadic:       true,
 					ReturnType:     &types.Void{},
 				},
 				Executor: func(_ evaluator.Object, arguments []evaluator.Object) evaluator.Object {
-					printFn(arguments[0].ToString() + "\n")
+					printFn(joinArguments(arguments) + "\n")
 					return nil
 				},
 			},
 			"print": &BuiltinFunction{
 				FunctionType: &types.Function{
 					ParameterTypes: []types.Type{anyBuiltin},
+					Variadic:       true,
 					ReturnType:     &types.Void{},
 				},
 				Executor: func(_ evaluator.Object, arguments []evaluator.Object) evaluator.Object {
-					printFn(arguments[0].ToString())
+					printFn(joinArguments(arguments))
 					return nil
 				},
 			},
@@ -204,12 +216,184 @@ func makeBuiltinObjects(printFn func(string), promptFn func(string) string) map[
 					ReturnType:     &types.Int{},
 				},
 				Executor: func(this evaluator.Object, _ []evaluator.Object) evaluator.Object {
-					// TODO throw error if invalid
-					value, _ := strconv.ParseInt(this.ToString(), 10, 64)
+					value, err := strconv.ParseInt(this.ToString(), 10, 64)
+					if err != nil {
+						return evaluator.NewErrorWithKind("ParseError", "Cannot parse '%s' as int", this.ToString())
+					}
 					return &evaluator.IntegerObject{Value: value}
 				},
 			},
 		},
+		arrayBuiltin: {
+			"length": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{},
+					ReturnType:     &types.Int{},
+				},
+				Executor: func(this evaluator.Object, _ []evaluator.Object) evaluator.Object {
+					return &evaluator.IntegerObject{Value: int64(len(this.(*evaluator.ArrayObject).Elements))}
+				},
+			},
+			"push": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{elementTypeParameter},
+					ReturnType:     &types.Void{},
+				},
+				Executor: func(this evaluator.Object, arguments []evaluator.Object) evaluator.Object {
+					array := this.(*evaluator.ArrayObject)
+					array.Elements = append(array.Elements, arguments[0])
+					return nil
+				},
+			},
+			"pop": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{},
+					ReturnType:     elementTypeParameter,
+				},
+				Executor: func(this evaluator.Object, _ []evaluator.Object) evaluator.Object {
+					array := this.(*evaluator.ArrayObject)
+					if len(array.Elements) == 0 {
+						return evaluator.NewError("Cannot pop from empty array")
+					}
+					last := array.Elements[len(array.Elements)-1]
+					array.Elements = array.Elements[:len(array.Elements)-1]
+					return last
+				},
+			},
+			"slice": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{&types.Int{}, &types.Int{}},
+					ReturnType:     arrayBuiltin,
+				},
+				Executor: func(this evaluator.Object, arguments []evaluator.Object) evaluator.Object {
+					array := this.(*evaluator.ArrayObject)
+					start := arguments[0].(*evaluator.IntegerObject).Value
+					end := arguments[1].(*evaluator.IntegerObject).Value
+					if start < 0 || end > int64(len(array.Elements)) || start > end {
+						return evaluator.NewError("Slice [%d:%d] out of bounds for length %d", start, end,
+							len(array.Elements))
+					}
+					elements := append([]evaluator.Object{}, array.Elements[start:end]...)
+					return &evaluator.ArrayObject{Elements: elements, ElementType: array.ElementType}
+				},
+			},
+			"map": &BuiltinFunction{
+				FunctionType: &types.Function{
+					TypeParameters: []*types.TypeParameter{mappedTypeParameter},
+					ParameterTypes: []types.Type{
+						&types.Function{
+							ParameterTypes: []types.Type{elementTypeParameter},
+							ReturnType:     mappedTypeParameter,
+						},
+					},
+					ReturnType: &types.Array{ElementType: mappedTypeParameter},
+				},
+				Executor: func(this evaluator.Object, arguments []evaluator.Object) evaluator.Object {
+					function := arguments[0].(evaluator.Function)
+					elements := make([]evaluator.Object, 0)
+					for _, element := range this.(*evaluator.ArrayObject).Elements {
+						mapped := evaluator.CallFunction(function, []evaluator.Object{element})
+						if _, isError := mapped.(*evaluator.ErrorObject); isError {
+							return mapped
+						}
+						elements = append(elements, mapped)
+					}
+					var elementType types.Type = anyBuiltin
+					if functionType, isFunction := function.Type().(*types.Function); isFunction {
+						elementType = functionType.ReturnType
+					}
+					return &evaluator.ArrayObject{Elements: elements, ElementType: elementType}
+				},
+			},
+			"filter": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{
+						&types.Function{
+							ParameterTypes: []types.Type{elementTypeParameter},
+							ReturnType:     &types.Bool{},
+						},
+					},
+					ReturnType: arrayBuiltin,
+				},
+				Executor: func(this evaluator.Object, arguments []evaluator.Object) evaluator.Object {
+					function := arguments[0].(evaluator.Function)
+					array := this.(*evaluator.ArrayObject)
+					elements := make([]evaluator.Object, 0)
+					for _, element := range array.Elements {
+						keep := evaluator.CallFunction(function, []evaluator.Object{element})
+						switch keep := keep.(type) {
+						case *evaluator.ErrorObject:
+							return keep
+						case *evaluator.BooleanObject:
+							if keep.Value {
+								elements = append(elements, element)
+							}
+						}
+					}
+					return &evaluator.ArrayObject{Elements: elements, ElementType: array.ElementType}
+				},
+			},
+		},
+		mapBuiltin: {
+			"length": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{},
+					ReturnType:     &types.Int{},
+				},
+				Executor: func(this evaluator.Object, _ []evaluator.Object) evaluator.Object {
+					return &evaluator.IntegerObject{Value: int64(len(this.(*evaluator.MapObject).Keys))}
+				},
+			},
+			"has": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{keyTypeParameter},
+					ReturnType:     &types.Bool{},
+				},
+				Executor: func(this evaluator.Object, arguments []evaluator.Object) evaluator.Object {
+					_, ok := this.(*evaluator.MapObject).Get(arguments[0].(evaluator.Hashable))
+					return &evaluator.BooleanObject{Value: ok}
+				},
+			},
+			"delete": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{keyTypeParameter},
+					ReturnType:     &types.Void{},
+				},
+				Executor: func(this evaluator.Object, arguments []evaluator.Object) evaluator.Object {
+					this.(*evaluator.MapObject).Delete(arguments[0].(evaluator.Hashable))
+					return nil
+				},
+			},
+			"keys": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{},
+					ReturnType:     &types.Array{ElementType: keyTypeParameter},
+				},
+				Executor: func(this evaluator.Object, _ []evaluator.Object) evaluator.Object {
+					mapObject := this.(*evaluator.MapObject)
+					keys := make([]evaluator.Object, 0)
+					for _, key := range mapObject.Keys {
+						keys = append(keys, key)
+					}
+					return &evaluator.ArrayObject{Elements: keys, ElementType: mapObject.KeyType}
+				},
+			},
+			"values": &BuiltinFunction{
+				FunctionType: &types.Function{
+					ParameterTypes: []types.Type{},
+					ReturnType:     &types.Array{ElementType: valueTypeParameter},
+				},
+				Executor: func(this evaluator.Object, _ []evaluator.Object) evaluator.Object {
+					mapObject := this.(*evaluator.MapObject)
+					values := make([]evaluator.Object, 0)
+					for _, key := range mapObject.Keys {
+						value, _ := mapObject.Get(key)
+						values = append(values, value)
+					}
+					return &evaluator.ArrayObject{Elements: values, ElementType: mapObject.ValueType}
+				},
+			},
+		},
 	}
 }
 
@@ -244,3 +428,11 @@ func NewContextAndEnvironmentWithIO(printFn func(string), promptFn func(string)
 	}
 	return context, environment
 }
+
+func joinArguments(arguments []evaluator.Object) string {
+	parts := make([]string, len(arguments))
+	for i, argument := range arguments {
+		parts[i] = argument.ToString()
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"bananascript/src/parser"
 	"bananascript/src/types"
 	"reflect"
 )
@@ -10,6 +11,7 @@ type Environment struct {
 	parent           *Environment
 	store            map[string]Object
 	typeEnvironments map[types.Type]*Environment
+	modules          map[*parser.Module]*Environment
 }
 
 func NewEnvironment(context *types.Context) *Environment {
@@ -20,6 +22,22 @@ func ExtendEnvironment(parent *Environment, context *types.Context) *Environment
 	return &Environment{context: context, parent: parent, store: make(map[string]Object), typeEnvironments: make(map[types.Type]*Environment)}
 }
 
+// Copy returns an environment with the same parent and a copy of the objects defined in this environment
+func (environment *Environment) Copy() *Environment {
+	copied := ExtendEnvironment(environment.parent, environment.context)
+	for name, object := range environment.store {
+		copied.store[name] = object
+	}
+	return copied
+}
+
+func (environment *Environment) Root() *Environment {
+	for environment.parent != nil {
+		environment = environment.parent
+	}
+	return environment
+}
+
 func (environment *Environment) GetObjectStrict(name string) (Object, bool) {
 	object, ok := environment.store[name]
 	return object, ok
@@ -35,7 +53,7 @@ func (environment *Environment) GetObject(name string) (Object, bool) {
 
 func (environment *Environment) GetTypeMember(object Object, parentType types.Type, name string) (Object, bool) {
 	for theType, typeStore := range environment.typeEnvironments {
-		if theType.IsAssignable(parentType, environment.context) {
+		if types.Unify(theType, parentType, make(types.Bindings), environment.context) {
 			object, ok := typeStore.GetObject(name)
 			if ok {
 				return object, ok
@@ -66,6 +84,14 @@ func (environment *Environment) DefineTypeMember(parentType types.Type, name str
 	return member, true
 }
 
+func (environment *Environment) ImportTypeMembers(module *Environment) {
+	for parentType, typeEnvironment := range module.typeEnvironments {
+		for name, member := range typeEnvironment.store {
+			environment.DefineTypeMember(parentType, name, member)
+		}
+	}
+}
+
 func (environment *Environment) AssignObject(name string, value Object) (Object, bool) {
 	if _, exists := environment.GetObjectStrict(name); exists {
 		return environment.DefineObject(name, value)
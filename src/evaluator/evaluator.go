@@ -3,11 +3,24 @@ package evaluator
 import (
 	"bananascript/src/parser"
 	"bananascript/src/token"
+	"bananascript/src/types"
 	"fmt"
+	"math"
 	"reflect"
 )
 
 func Eval(node parser.Node, environment *Environment) Object {
+	object := evalNode(node, environment)
+	if errorObject, isError := object.(*ErrorObject); isError && errorObject.Line == 0 {
+		// record where the error was raised, the innermost node wins
+		if nodeToken := node.Token(); nodeToken != nil {
+			errorObject.File, errorObject.Line, errorObject.Col = nodeToken.File, nodeToken.Line, nodeToken.Col
+		}
+	}
+	return object
+}
+
+func evalNode(node parser.Node, environment *Environment) Object {
 	switch node := node.(type) {
 	case *parser.Program:
 		return evalProgram(node, environment)
@@ -15,6 +28,8 @@ func Eval(node parser.Node, environment *Environment) Object {
 		return Eval(node.Expression, environment)
 	case *parser.StringLiteral:
 		return &StringObject{Value: node.Value}
+	case *parser.InterpolatedStringLiteral:
+		return evalInterpolatedStringLiteral(node, environment)
 	case *parser.IntegerLiteral:
 		return &IntegerObject{Value: node.Value}
 	case *parser.FloatLiteral:
@@ -29,6 +44,14 @@ func Eval(node parser.Node, environment *Environment) Object {
 		return evalIdentifierExpression(node, environment)
 	case *parser.InfixExpression:
 		return evalInfixExpression(node, environment)
+	case *parser.ConditionalExpression:
+		return evalConditionalExpression(node, environment)
+	case *parser.IsExpression:
+		return evalIsExpression(node, environment)
+	case *parser.MatchExpression:
+		return evalMatchExpression(node, environment)
+	case *parser.MatchStatement:
+		return evalMatchStatement(node, environment)
 	case *parser.PrefixExpression:
 		return evalPrefixExpression(node, environment)
 	case *parser.CallExpression:
@@ -37,6 +60,8 @@ func Eval(node parser.Node, environment *Environment) Object {
 		return evalAssignmentExpression(node, environment)
 	case *parser.LetStatement:
 		return evalLetStatement(node, environment)
+	case *parser.DestructuringStatement:
+		return evalDestructuringStatement(node, environment)
 	case *parser.FunctionDefinitionStatement:
 		return evalFunctionDefinitionStatement(node, environment)
 	case *parser.ReturnStatement:
@@ -47,10 +72,42 @@ func Eval(node parser.Node, environment *Environment) Object {
 		return evalIfStatement(node, environment)
 	case *parser.WhileStatement:
 		return evalWhileStatement(node, environment)
+	case *parser.ForStatement:
+		return evalForStatement(node, environment)
+	case *parser.ForInStatement:
+		return evalForInStatement(node, environment)
 	case *parser.IncrementExpression:
 		return evalIncrementExpression(node, environment)
 	case *parser.MemberAccessExpression:
 		return evalMemberAccessExpression(node, environment)
+	case *parser.ArrayLiteral:
+		return evalArrayLiteral(node, environment)
+	case *parser.MapLiteral:
+		return evalMapLiteral(node, environment)
+	case *parser.FunctionLiteral:
+		return evalFunctionLiteral(node, environment)
+	case *parser.StructLiteral:
+		return evalStructLiteral(node, environment)
+	case *parser.EnumLiteral:
+		return evalEnumLiteral(node, environment)
+	case *parser.TupleLiteral:
+		return evalTupleLiteral(node, environment)
+	case *parser.MemberAssignmentExpression:
+		return evalMemberAssignmentExpression(node, environment)
+	case *parser.IndexExpression:
+		return evalIndexExpression(node, environment)
+	case *parser.IndexAssignmentExpression:
+		return evalIndexAssignmentExpression(node, environment)
+	case *parser.BreakStatement:
+		return &BreakObject{Label: labelName(node.Label)}
+	case *parser.ContinueStatement:
+		return &ContinueObject{Label: labelName(node.Label)}
+	case *parser.ImportStatement:
+		return evalImportStatement(node, environment)
+	case *parser.ThrowStatement:
+		return evalThrowStatement(node, environment)
+	case *parser.TryStatement:
+		return evalTryStatement(node, environment)
 	case *parser.TypeDefinitionStatement:
 		return nil
 	}
@@ -86,6 +143,10 @@ func evalPrefixExpression(prefixExpression *parser.PrefixExpression, environment
 		case *FloatObject:
 			return &FloatObject{Value: -object.Value}
 		}
+	case token.Tilde:
+		if object, isInteger := object.(*IntegerObject); isInteger {
+			return &IntegerObject{Value: ^object.Value}
+		}
 	}
 
 	return NewError("Unknown prefix operator")
@@ -101,6 +162,11 @@ func evalInfixExpression(infixExpression *parser.InfixExpression, environment *E
 		return &BooleanObject{Value: false}
 	} else if infixExpression.Operator == token.LogicalOr && implicitBoolConversion(leftObject) {
 		return &BooleanObject{Value: true}
+	} else if infixExpression.Operator == token.Nullish {
+		if _, isNull := leftObject.(*NullObject); !isNull {
+			return leftObject
+		}
+		return Eval(infixExpression.Right, environment)
 	}
 
 	rightObject := Eval(infixExpression.Right, environment)
@@ -111,11 +177,45 @@ func evalInfixExpression(infixExpression *parser.InfixExpression, environment *E
 		return &BooleanObject{Value: implicitBoolConversion(rightObject)}
 	}
 
-	switch infixExpression.Operator {
+	return evalInfixOperator(infixExpression.Operator, leftObject, rightObject)
+}
+
+func evalIsExpression(isExpression *parser.IsExpression, environment *Environment) Object {
+	object := Eval(isExpression.Expression, environment)
+	if isError(object) {
+		return object
+	}
+	return &BooleanObject{Value: isExpression.Type.IsAssignable(object.Type(), isExpression.Context)}
+}
+
+func evalConditionalExpression(conditionalExpression *parser.ConditionalExpression, environment *Environment) Object {
+
+	condition := Eval(conditionalExpression.Condition, environment)
+	if isError(condition) {
+		return condition
+	}
+
+	if implicitBoolConversion(condition) {
+		return Eval(conditionalExpression.Consequence, environment)
+	} else {
+		return Eval(conditionalExpression.Alternative, environment)
+	}
+}
+
+func evalInfixOperator(operator token.Type, leftObject Object, rightObject Object) Object {
+	switch operator {
 	case token.EQ:
 		return &BooleanObject{Value: evalEquals(leftObject, rightObject)}
 	case token.NEQ:
 		return &BooleanObject{Value: !evalEquals(leftObject, rightObject)}
+	case token.DotDot:
+		// ranges are only lazy in for-in loops, anywhere else they are arrays
+		start, end := leftObject.(*IntegerObject).Value, rightObject.(*IntegerObject).Value
+		elements := make([]Object, 0)
+		for i := start; i < end; i++ {
+			elements = append(elements, &IntegerObject{Value: i})
+		}
+		return &ArrayObject{Elements: elements, ElementType: &types.Int{}}
 	case token.LT:
 		return evalNumericInfix(
 			leftObject, rightObject,
@@ -160,7 +260,12 @@ func evalInfixExpression(infixExpression *parser.InfixExpression, environment *E
 	case token.Slash:
 		return evalNumericInfix(
 			leftObject, rightObject,
-			func(left int64, right int64) Object { return &IntegerObject{Value: left / right} },
+			func(left int64, right int64) Object {
+				if right == 0 {
+					return NewError("Division by zero")
+				}
+				return &IntegerObject{Value: left / right}
+			},
 			func(left float64, right float64) Object { return &FloatObject{Value: left / right} },
 		)
 	case token.Star:
@@ -169,6 +274,57 @@ func evalInfixExpression(infixExpression *parser.InfixExpression, environment *E
 			func(left int64, right int64) Object { return &IntegerObject{Value: left * right} },
 			func(left float64, right float64) Object { return &FloatObject{Value: left * right} },
 		)
+	case token.Percent:
+		return evalNumericInfix(
+			leftObject, rightObject,
+			func(left int64, right int64) Object {
+				if right == 0 {
+					return NewError("Division by zero")
+				}
+				return &IntegerObject{Value: left % right}
+			},
+			func(left float64, right float64) Object { return &FloatObject{Value: math.Mod(left, right)} },
+		)
+	case token.Power:
+		return evalNumericInfix(
+			leftObject, rightObject,
+			func(left int64, right int64) Object {
+				if right < 0 {
+					return NewError("Negative integer exponent")
+				}
+				result := int64(1)
+				for ; right > 0; right >>= 1 {
+					if right&1 == 1 {
+						result *= left
+					}
+					left *= left
+				}
+				return &IntegerObject{Value: result}
+			},
+			func(left float64, right float64) Object { return &FloatObject{Value: math.Pow(left, right)} },
+		)
+	case token.Amp:
+		return evalIntegerInfix(leftObject, rightObject, func(left int64, right int64) Object {
+			return &IntegerObject{Value: left & right}
+		})
+	case token.Pipe:
+		return evalIntegerInfix(leftObject, rightObject, func(left int64, right int64) Object {
+			return &IntegerObject{Value: left | right}
+		})
+	case token.Caret:
+		return evalIntegerInfix(leftObject, rightObject, func(left int64, right int64) Object {
+			return &IntegerObject{Value: left ^ right}
+		})
+	case token.ShiftLeft, token.ShiftRight:
+		return evalIntegerInfix(leftObject, rightObject, func(left int64, right int64) Object {
+			if right < 0 {
+				return NewError("Negative shift count")
+			}
+			if operator == token.ShiftLeft {
+				return &IntegerObject{Value: left << right}
+			}
+			return &IntegerObject{Value: left >> right}
+		})
 	default:
 		return NewError("Unknown infix operator")
 	}
@@ -198,14 +354,51 @@ func evalNumericInfix(left Object, right Object, intConstructor func(left int64,
 	return NewError("Invalid infix operator")
 }
 
+func evalAssignedValue(operator token.Type, current func() Object, expression parser.Expression, environment *Environment) Object {
+	if operator == token.Assign {
+		return Eval(expression, environment)
+	}
+
+	currentObject := current()
+	if isError(currentObject) {
+		return currentObject
+	}
+	if operator == token.NullishAssign {
+		if _, isNull := currentObject.(*NullObject); !isNull {
+			return currentObject
+		}
+		return Eval(expression, environment)
+	}
+
+	value := Eval(expression, environment)
+	if isError(value) {
+		return value
+	}
+	return evalInfixOperator(token.CompoundAssignments[operator], currentObject, value)
+}
+
+func evalIntegerInfix(left Object, right Object, constructor func(left int64, right int64) Object) Object {
+	leftInteger, leftIsInteger := left.(*IntegerObject)
+	rightInteger, rightIsInteger := right.(*IntegerObject)
+	if !leftIsInteger || !rightIsInteger {
+		return NewError("Invalid infix operator")
+	}
+	return constructor(leftInteger.Value, rightInteger.Value)
+}
+
 func evalAssignmentExpression(assignmentExpression *parser.AssignmentExpression, environment *Environment) Object {
 
-	object := Eval(assignmentExpression.Expression, environment)
+	name := assignmentExpression.Name.Value
+	object := evalAssignedValue(assignmentExpression.AssignToken.Type, func() Object {
+		if current, ok := environment.GetObject(name); ok {
+			return current
+		}
+		return NewError("Cannot resolve variable")
+	}, assignmentExpression.Expression, environment)
 	if isError(object) {
 		return object
 	}
 
-	name := assignmentExpression.Name.Value
 	if object, ok := environment.AssignObject(name, object); ok {
 		return object
 	} else {
@@ -218,23 +411,43 @@ func evalCallExpression(callExpression *parser.CallExpression, environment *Envi
 	switch function := function.(type) {
 	case *ErrorObject:
 		return function
+	case *NullObject:
+		if member, isMember := callExpression.Function.(*parser.MemberAccessExpression); isMember && member.Optional {
+			return function
+		}
+		return NewError("Cannot call non-function")
 	case Function:
 		argumentObjects := make([]Object, 0)
-		for _, argument := range callExpression.Arguments {
-			argumentObjects = append(argumentObjects, Eval(argument, environment))
-		}
-		returned := function.Execute(argumentObjects)
-		switch returned := returned.(type) {
-		case *ReturnObject:
-			return returned.Object
-		default:
-			return returned
+		for i, argument := range callExpression.Arguments {
+			argumentObject := Eval(argument, environment)
+			if isError(argumentObject) {
+				return argumentObject
+			}
+			index := i
+			if callExpression.ParameterIndices != nil {
+				index = callExpression.ParameterIndices[i]
+			}
+			for len(argumentObjects) <= index {
+				argumentObjects = append(argumentObjects, nil)
+			}
+			argumentObjects[index] = argumentObject
 		}
+		return CallFunction(function, argumentObjects)
 	default:
 		return NewError("Cannot call non-function")
 	}
 }
 
+func CallFunction(function Function, arguments []Object) Object {
+	returned := function.Execute(arguments)
+	switch returned := returned.(type) {
+	case *ReturnObject:
+		return returned.Object
+	default:
+		return returned
+	}
+}
+
 func evalIdentifierExpression(identifier *parser.Identifier, environment *Environment) Object {
 	if object, exists := environment.GetObject(identifier.Value); exists {
 		return object
@@ -255,17 +468,31 @@ func evalLetStatement(letStatement *parser.LetStatement, environment *Environmen
 	return nil
 }
 
-func evalFunctionDefinitionStatement(funcStatement *parser.FunctionDefinitionStatement, environment *Environment) Object {
+func evalDestructuringStatement(destructuringStatement *parser.DestructuringStatement, environment *Environment) Object {
 
-	name := funcStatement.Name.Value
+	object := Eval(destructuringStatement.Value, environment)
+	if isError(object) {
+		return object
+	}
 
-	identifiers := make([]*parser.Identifier, 0)
-	for _, parameter := range funcStatement.Parameters {
-		identifiers = append(identifiers, parameter.Name)
+	tupleObject, isTuple := object.(*TupleObject)
+	if !isTuple || len(tupleObject.Elements) != len(destructuringStatement.Names) {
+		return NewError("Cannot destructure %s", object.ToString())
 	}
+	for i, name := range destructuringStatement.Names {
+		if name.Value != "_" {
+			environment.DefineObject(name.Value, tupleObject.Elements[i])
+		}
+	}
+	return nil
+}
+
+func evalFunctionDefinitionStatement(funcStatement *parser.FunctionDefinitionStatement, environment *Environment) Object {
+
+	name := funcStatement.Name.Value
 
 	object := &FunctionObject{
-		Parameters:   identifiers,
+		Parameters:   funcStatement.Parameters,
 		Body:         funcStatement.Body,
 		Environment:  environment,
 		Context:      funcStatement.FunctionContext,
@@ -280,6 +507,16 @@ func evalFunctionDefinitionStatement(funcStatement *parser.FunctionDefinitionSta
 	return nil
 }
 
+func evalFunctionLiteral(functionLiteral *parser.FunctionLiteral, environment *Environment) Object {
+	return &FunctionObject{
+		Parameters:   functionLiteral.Parameters,
+		Body:         functionLiteral.Body,
+		Environment:  environment,
+		Context:      functionLiteral.FunctionContext,
+		FunctionType: functionLiteral.FunctionType,
+	}
+}
+
 func evalReturnStatement(returnStatement *parser.ReturnStatement, environment *Environment) Object {
 	object := Eval(returnStatement.Expression, environment)
 	if isError(object) {
@@ -288,6 +525,97 @@ func evalReturnStatement(returnStatement *parser.ReturnStatement, environment *E
 	return &ReturnObject{Object: object}
 }
 
+func evalImportStatement(importStatement *parser.ImportStatement, environment *Environment) Object {
+	moduleEnvironment, errorObject := evalModule(importStatement.Module, environment.Root())
+	if errorObject != nil {
+		return errorObject
+	}
+
+	environment.ImportTypeMembers(moduleEnvironment)
+	exports := make(map[string]Object)
+	for name := range importStatement.Module.Program.Context.MemberTypes() {
+		exports[name], _ = moduleEnvironment.GetObjectStrict(name)
+	}
+	if importStatement.Alias != nil {
+		environment.DefineObject(importStatement.Alias.Value, &StructObject{Fields: exports, StructType: importStatement.AliasType})
+	} else {
+		for name, object := range exports {
+			environment.DefineObject(name, object)
+		}
+	}
+	return nil
+}
+
+func evalModule(module *parser.Module, rootEnvironment *Environment) (*Environment, *ErrorObject) {
+	if rootEnvironment.modules == nil {
+		rootEnvironment.modules = make(map[*parser.Module]*Environment)
+	}
+	if moduleEnvironment, ok := rootEnvironment.modules[module]; ok {
+		return moduleEnvironment, nil
+	}
+
+	moduleEnvironment := ExtendEnvironment(rootEnvironment, module.Program.Context)
+	rootEnvironment.modules[module] = moduleEnvironment
+	for _, statement := range module.Program.Statements {
+		if errorObject, isError := Eval(statement, moduleEnvironment).(*ErrorObject); isError {
+			return nil, errorObject
+		}
+	}
+	return moduleEnvironment, nil
+}
+
+func evalThrowStatement(throwStatement *parser.ThrowStatement, environment *Environment) Object {
+	object := Eval(throwStatement.Value, environment)
+	switch object := object.(type) {
+	case *ErrorObject:
+		return object
+	case *StringObject:
+		return NewErrorWithKind("Error", "%s", object.Value)
+	case *StructObject:
+		errorObject := &ErrorObject{
+			Kind:    object.Fields["kind"].ToString(),
+			Message: object.Fields["message"].ToString(),
+		}
+		if file, isString := object.Fields["file"].(*StringObject); isString {
+			errorObject.File = &file.Value
+		}
+		if line, isInt := object.Fields["line"].(*IntegerObject); isInt {
+			errorObject.Line = int(line.Value)
+		}
+		if col, isInt := object.Fields["col"].(*IntegerObject); isInt {
+			errorObject.Col = int(col.Value)
+		}
+		return errorObject
+	}
+	return NewError("Cannot throw %s", object.ToString())
+}
+
+func evalTryStatement(tryStatement *parser.TryStatement, environment *Environment) Object {
+	object := Eval(tryStatement.Body, environment)
+
+	if errorObject, isError := object.(*ErrorObject); isError && tryStatement.Catch != nil {
+		catchEnvironment := ExtendEnvironment(environment, tryStatement.CatchContext)
+		if tryStatement.CatchVariable != nil {
+			catchEnvironment.DefineObject(tryStatement.CatchVariable.Value, errorObject.ToStruct())
+		}
+		object = Eval(tryStatement.Catch, catchEnvironment)
+	}
+
+	if tryStatement.Finally != nil {
+		switch finallyObject := Eval(tryStatement.Finally, environment); finallyObject.(type) {
+		case *ErrorObject, *ReturnObject, *BreakObject, *ContinueObject:
+			return finallyObject
+		}
+	}
+
+	switch object.(type) {
+	case *ErrorObject, *ReturnObject, *BreakObject, *ContinueObject:
+		return object
+	default:
+		return nil
+	}
+}
+
 func evalBlockStatement(blockStatement *parser.BlockStatement, environment *Environment) Object {
 	newEnvironment := ExtendEnvironment(environment, blockStatement.Context)
 
@@ -295,7 +623,7 @@ func evalBlockStatement(blockStatement *parser.BlockStatement, environment *Envi
 		object := Eval(statement, newEnvironment)
 		if object != nil {
 			switch object := object.(type) {
-			case *ErrorObject, *ReturnObject:
+			case *ErrorObject, *ReturnObject, *BreakObject, *ContinueObject:
 				return object
 			default:
 				continue
@@ -318,7 +646,62 @@ func evalIfStatement(ifStatement *parser.IfStatement, environment *Environment)
 		object = Eval(ifStatement.Alternative, ExtendEnvironment(environment, ifStatement.AlternativeContext))
 	}
 	switch object.(type) {
-	case *ErrorObject, *ReturnObject:
+	case *ErrorObject, *ReturnObject, *BreakObject, *ContinueObject:
+		return object
+	default:
+		return nil
+	}
+}
+
+func evalMatchExpression(matchExpression *parser.MatchExpression, environment *Environment) Object {
+
+	subject := Eval(matchExpression.Subject, environment)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range matchExpression.Arms {
+		armEnvironment := ExtendEnvironment(environment, arm.Context)
+		matched := arm.Wildcard || arm.TypePattern != nil && arm.TypePattern.IsAssignable(subject.Type(), arm.Context)
+		for _, pattern := range arm.Patterns {
+			if matched {
+				break
+			}
+			if enumPattern, isEnumPattern := pattern.(*parser.EnumPattern); isEnumPattern {
+				matched = evalEnumPattern(enumPattern, subject, armEnvironment)
+				continue
+			}
+			value := Eval(pattern, environment)
+			if isError(value) {
+				return value
+			}
+			matched = evalEquals(subject, value)
+		}
+		if matched {
+			return Eval(arm.Body, armEnvironment)
+		}
+	}
+
+	return nil
+}
+
+func evalEnumPattern(enumPattern *parser.EnumPattern, subject Object, armEnvironment *Environment) bool {
+	enumObject, isEnum := subject.(*EnumObject)
+	if !isEnum || enumObject.Variant != enumPattern.Variant.Value || enumPattern.Type.Root() != enumObject.EnumType.Root() {
+		return false
+	}
+	for i, binding := range enumPattern.Bindings {
+		if binding.Value != "_" {
+			armEnvironment.DefineObject(binding.Value, enumObject.Values[i])
+		}
+	}
+	return true
+}
+
+func evalMatchStatement(matchStatement *parser.MatchStatement, environment *Environment) Object {
+	object := evalMatchExpression(matchStatement.Match, environment)
+	switch object.(type) {
+	case *ErrorObject, *ReturnObject, *BreakObject, *ContinueObject:
 		return object
 	default:
 		return nil
@@ -335,15 +718,123 @@ func evalWhileStatement(whileStatement *parser.WhileStatement, environment *Envi
 			return nil
 		}
 		object := Eval(whileStatement.Statement, ExtendEnvironment(environment, whileStatement.StatementContext))
-		switch object := object.(type) {
-		case *ErrorObject, *ReturnObject:
+		if result, done := evalLoopControl(object, whileStatement.Label); done {
+			return result
+		}
+	}
+}
+
+func evalForStatement(forStatement *parser.ForStatement, environment *Environment) Object {
+	loopEnvironment := ExtendEnvironment(environment, forStatement.LoopContext)
+	if forStatement.Initializer != nil {
+		if object := Eval(forStatement.Initializer, loopEnvironment); isError(object) {
 			return object
-		default:
-			continue
+		}
+	}
+	for {
+		if forStatement.Condition != nil {
+			condition := Eval(forStatement.Condition, loopEnvironment)
+			if isError(condition) {
+				return condition
+			}
+			if !implicitBoolConversion(condition) {
+				return nil
+			}
+		}
+		object := Eval(forStatement.Statement, ExtendEnvironment(loopEnvironment, forStatement.StatementContext))
+		if result, done := evalLoopControl(object, forStatement.Label); done {
+			return result
+		}
+		// each iteration gets its own loop variables, so closures keep the values of their iteration
+		loopEnvironment = loopEnvironment.Copy()
+		if forStatement.Update != nil {
+			if update := Eval(forStatement.Update, loopEnvironment); isError(update) {
+				return update
+			}
 		}
 	}
 }
 
+func evalForInStatement(forInStatement *parser.ForInStatement, environment *Environment) Object {
+
+	evalIteration := func(element Object) Object {
+		iterationEnvironment := ExtendEnvironment(environment, forInStatement.StatementContext)
+		iterationEnvironment.DefineObject(forInStatement.Variable.Value, element)
+		return Eval(forInStatement.Statement, iterationEnvironment)
+	}
+
+	if rangeExpression, isRange := forInStatement.Iterable.(*parser.InfixExpression); isRange && rangeExpression.Operator == token.DotDot {
+		start := Eval(rangeExpression.Left, environment)
+		if isError(start) {
+			return start
+		}
+		end := Eval(rangeExpression.Right, environment)
+		if isError(end) {
+			return end
+		}
+		for i := start.(*IntegerObject).Value; i < end.(*IntegerObject).Value; i++ {
+			object := evalIteration(&IntegerObject{Value: i})
+			if result, done := evalLoopControl(object, forInStatement.Label); done {
+				return result
+			}
+		}
+		return nil
+	}
+
+	iterable := Eval(forInStatement.Iterable, environment)
+	var elements []Object
+	switch iterable := iterable.(type) {
+	case *ErrorObject:
+		return iterable
+	case *StringObject:
+		for _, char := range iterable.Value {
+			elements = append(elements, &StringObject{Value: string(char)})
+		}
+	case *ArrayObject:
+		elements = append(elements, iterable.Elements...)
+	case *MapObject:
+		for _, key := range iterable.Keys {
+			elements = append(elements, key)
+		}
+	default:
+		return NewError("Cannot iterate over %s", iterable.ToString())
+	}
+
+	for _, element := range elements {
+		object := evalIteration(element)
+		if result, done := evalLoopControl(object, forInStatement.Label); done {
+			return result
+		}
+	}
+	return nil
+}
+
+func evalLoopControl(object Object, label *parser.Identifier) (Object, bool) {
+	switch object := object.(type) {
+	case *ErrorObject, *ReturnObject:
+		return object, true
+	case *BreakObject:
+		if object.Label == "" || object.Label == labelName(label) {
+			return nil, true
+		}
+		return object, true
+	case *ContinueObject:
+		if object.Label == "" || object.Label == labelName(label) {
+			return nil, false
+		}
+		return object, true
+	default:
+		return nil, false
+	}
+}
+
+func labelName(label *parser.Identifier) string {
+	if label == nil {
+		return ""
+	}
+	return label.Value
+}
+
 func evalIncrementExpression(incrementExpression *parser.IncrementExpression, environment *Environment) Object {
 
 	object, exists := environment.GetObject(incrementExpression.Name.Value)
@@ -351,34 +842,26 @@ func evalIncrementExpression(incrementExpression *parser.IncrementExpression, en
 		return NewError("Cannot resolve identifier")
 	}
 
+	delta := int64(1)
+	if incrementExpression.Operator == token.Decrement {
+		delta = -1
+	}
+
+	// other bindings may share the object, so a new one is assigned instead of changing it in place
+	var newObject Object
 	switch object := object.(type) {
 	case *IntegerObject:
-		oldValue := object.Value
-		if incrementExpression.Operator == token.Increment {
-			object.Value++
-		} else {
-			object.Value--
-		}
-		if incrementExpression.Pre {
-			return object
-		} else {
-			return &IntegerObject{Value: oldValue}
-		}
+		newObject = &IntegerObject{Value: object.Value + delta}
 	case *FloatObject:
-		oldValue := object.Value
-		if incrementExpression.Operator == token.Increment {
-			object.Value++
-		} else {
-			object.Value--
-		}
-		if incrementExpression.Pre {
-			return object
-		} else {
-			return &FloatObject{Value: oldValue}
-		}
+		newObject = &FloatObject{Value: object.Value + float64(delta)}
+	default:
+		return NewError("Cannot increment non-int")
 	}
-
-	return NewError("Cannot increment non-int")
+	environment.AssignObject(incrementExpression.Name.Value, newObject)
+	if incrementExpression.Pre {
+		return newObject
+	}
+	return object
 }
 
 func evalMemberAccessExpression(memberAccessExpression *parser.MemberAccessExpression, environment *Environment) Object {
@@ -387,6 +870,15 @@ func evalMemberAccessExpression(memberAccessExpression *parser.MemberAccessExpre
 	if isError(object) {
 		return object
 	}
+	if _, isNull := object.(*NullObject); isNull && memberAccessExpression.Optional {
+		return object
+	}
+
+	if structObject, isStruct := object.(*StructObject); isStruct {
+		if field, ok := structObject.Fields[memberAccessExpression.Member.Value]; ok {
+			return field
+		}
+	}
 
 	member, ok := environment.GetTypeMember(object, object.Type(), memberAccessExpression.Member.Value)
 	if !ok {
@@ -401,6 +893,184 @@ func evalMemberAccessExpression(memberAccessExpression *parser.MemberAccessExpre
 	}
 }
 
+func evalMemberAssignmentExpression(memberAssignmentExpression *parser.MemberAssignmentExpression, environment *Environment) Object {
+
+	object := Eval(memberAssignmentExpression.Member.Expression, environment)
+	if isError(object) {
+		return object
+	}
+
+	structObject, isStruct := object.(*StructObject)
+	if !isStruct {
+		return NewError("Cannot assign to member of %s", object.ToString())
+	}
+
+	name := memberAssignmentExpression.Member.Member.Value
+	value := evalAssignedValue(memberAssignmentExpression.AssignToken.Type, func() Object {
+		return structObject.Fields[name]
+	}, memberAssignmentExpression.Expression, environment)
+	if isError(value) {
+		return value
+	}
+
+	structObject.Fields[name] = value
+	return value
+}
+
+func evalEnumLiteral(enumLiteral *parser.EnumLiteral, environment *Environment) Object {
+	enumObject := &EnumObject{Variant: enumLiteral.Variant.Value, Values: make([]Object, 0), EnumType: enumLiteral.Type}
+	for _, argument := range enumLiteral.Arguments {
+		value := Eval(argument, environment)
+		if isError(value) {
+			return value
+		}
+		enumObject.Values = append(enumObject.Values, value)
+	}
+	return enumObject
+}
+
+func evalStructLiteral(structLiteral *parser.StructLiteral, environment *Environment) Object {
+	structObject := &StructObject{Fields: make(map[string]Object), StructType: structLiteral.Type}
+	for _, name := range structLiteral.Type.FieldNames {
+		structObject.Fields[name] = &NullObject{}
+	}
+	for i, fieldName := range structLiteral.FieldNames {
+		value := Eval(structLiteral.Values[i], environment)
+		if isError(value) {
+			return value
+		}
+		structObject.Fields[fieldName.Value] = value
+	}
+	return structObject
+}
+
+func evalArrayLiteral(arrayLiteral *parser.ArrayLiteral, environment *Environment) Object {
+	elements := make([]Object, 0)
+	for _, element := range arrayLiteral.Elements {
+		object := Eval(element, environment)
+		if isError(object) {
+			return object
+		}
+		elements = append(elements, object)
+	}
+	return &ArrayObject{Elements: elements, ElementType: arrayLiteral.Type.ElementType}
+}
+
+func evalInterpolatedStringLiteral(literal *parser.InterpolatedStringLiteral, environment *Environment) Object {
+	value := ""
+	for _, part := range literal.Parts {
+		object := Eval(part, environment)
+		if isError(object) {
+			return object
+		}
+		value += object.ToString()
+	}
+	return &StringObject{Value: value}
+}
+
+func evalTupleLiteral(tupleLiteral *parser.TupleLiteral, environment *Environment) Object {
+	elements := make([]Object, 0)
+	for _, element := range tupleLiteral.Elements {
+		object := Eval(element, environment)
+		if isError(object) {
+			return object
+		}
+		elements = append(elements, object)
+	}
+	return &TupleObject{Elements: elements}
+}
+
+func evalMapLiteral(mapLiteral *parser.MapLiteral, environment *Environment) Object {
+	mapObject := NewMapObject(mapLiteral.Type.KeyType, mapLiteral.Type.ValueType)
+	for i, keyExpression := range mapLiteral.Keys {
+		key := Eval(keyExpression, environment)
+		if isError(key) {
+			return key
+		}
+		value := Eval(mapLiteral.Values[i], environment)
+		if isError(value) {
+			return value
+		}
+		hashable, ok := key.(Hashable)
+		if !ok {
+			return NewError("Cannot use %s as map key", key.ToString())
+		}
+		mapObject.Set(hashable, value)
+	}
+	return mapObject
+}
+
+func evalIndexExpression(indexExpression *parser.IndexExpression, environment *Environment) Object {
+
+	object := Eval(indexExpression.Expression, environment)
+	if isError(object) {
+		return object
+	}
+
+	index := Eval(indexExpression.Index, environment)
+	if isError(index) {
+		return index
+	}
+
+	return evalIndex(object, index)
+}
+
+func evalIndex(object Object, index Object) Object {
+	switch object := object.(type) {
+	case *ArrayObject:
+		i := index.(*IntegerObject).Value
+		if i < 0 || i >= int64(len(object.Elements)) {
+			return NewError("Index %d out of bounds for length %d", i, len(object.Elements))
+		}
+		return object.Elements[i]
+	case *TupleObject:
+		return object.Elements[index.(*IntegerObject).Value]
+	case *MapObject:
+		value, ok := object.Get(index.(Hashable))
+		if !ok {
+			return NewError("Key %s does not exist", index.ToString())
+		}
+		return value
+	}
+
+	return NewError("Cannot index %s", object.ToString())
+}
+
+func evalIndexAssignmentExpression(indexAssignmentExpression *parser.IndexAssignmentExpression, environment *Environment) Object {
+
+	object := Eval(indexAssignmentExpression.Index.Expression, environment)
+	if isError(object) {
+		return object
+	}
+
+	index := Eval(indexAssignmentExpression.Index.Index, environment)
+	if isError(index) {
+		return index
+	}
+
+	value := evalAssignedValue(indexAssignmentExpression.AssignToken.Type, func() Object {
+		return evalIndex(object, index)
+	}, indexAssignmentExpression.Expression, environment)
+	if isError(value) {
+		return value
+	}
+
+	switch object := object.(type) {
+	case *ArrayObject:
+		i := index.(*IntegerObject).Value
+		if i < 0 || i >= int64(len(object.Elements)) {
+			return NewError("Index %d out of bounds for length %d", i, len(object.Elements))
+		}
+		object.Elements[i] = value
+		return value
+	case *MapObject:
+		object.Set(index.(Hashable), value)
+		return value
+	}
+
+	return NewError("Cannot index %s", object.ToString())
+}
+
 func implicitBoolConversion(object Object) bool {
 	switch object := object.(type) {
 	case *BooleanObject:
@@ -417,7 +1087,11 @@ func implicitBoolConversion(object Object) bool {
 }
 
 func NewError(format string, args ...interface{}) *ErrorObject {
-	return &ErrorObject{Message: fmt.Sprintf(format, args...)}
+	return NewErrorWithKind("RuntimeError", format, args...)
+}
+
+func NewErrorWithKind(kind string, format string, args ...interface{}) *ErrorObject {
+	return &ErrorObject{Kind: kind, Message: fmt.Sprintf(format, args...)}
 }
 
 func isError(object Object) bool {
@@ -5,6 +5,8 @@ import (
 	"bananascript/src/parser"
 	"bananascript/src/types"
 	"gotest.tools/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -34,6 +36,138 @@ func TestEvaluator(t *testing.T) {
 		"1 + 2 * 3 - 4;",
 		&IntegerObject{Value: 3},
 	)
+
+	assertObject(t,
+		"-7 % 3 + 2 ** 3 ** 2 + (6 & 3 | 8 ^ 1) + ~5 + (1 << 4 >> 2);",
+		&IntegerObject{Value: 520},
+	)
+
+	assertObject(t,
+		"let i := 10; i -= 3; i *= 2; i /= 3; let xs := [i]; xs[0] += 1; let o: int? = null; o ??= xs[0]; o ??= 0; o;",
+		&IntegerObject{Value: 5},
+	)
+
+	assertObject(t,
+		"let a := 5; a == 1 ? \"one\" : a == 5 ? \"five\" : \"other\";",
+		&StringObject{Value: "five"},
+	)
+
+	assertObject(t,
+		"let a := 2; let b := match a { 1 => \"one\", 2 | 3 => \"few\", _ => \"many\" }; b;",
+		&StringObject{Value: "few"},
+	)
+
+	assertObject(t,
+		"fn pick<T>(first: bool, a: T, b: T) T { return first ? a : b; } pick(false, \"a\", \"b\");",
+		&StringObject{Value: "b"},
+	)
+
+	assertObject(t,
+		"type pair<A, B> := struct { a: A; b: B; }; let p := pair<int, pair<int, int>> { a: 1, b: pair<int, int> { a: 2, b: 3 } }; p.b.b;",
+		&IntegerObject{Value: 3},
+	)
+
+	assertObject(t,
+		"let u: int | string = \"a\"; let r := 0; if u is int { r = u; } else { r = 2; } r;",
+		&IntegerObject{Value: 2},
+	)
+
+	assertObject(t,
+		"fn f(x: int?) int { if x == null { return -1; } return x * 2; } f(null) + f(3);",
+		&IntegerObject{Value: 5},
+	)
+
+	assertObject(t,
+		"fn (int)::double() int { return this * 2; } let a: int? = null; let b: int? = 3; (a?.double() ?? 10) + (b?.double() ?? 20);",
+		&IntegerObject{Value: 16},
+	)
+
+	assertObject(t,
+		"type shape := enum { circle(int); rect(int, int); }; fn area(s: shape) int { return match s { shape.circle(r) => 3 * r * r, shape.rect(w, h) => w * h }; } area(shape.circle(2)) + area(shape.rect(2, 3));",
+		&IntegerObject{Value: 18},
+	)
+
+	assertObject(t,
+		"fn divmod(a: int, b: int) (int, int) { return (a / b, a % b); } let (q, r) := divmod(17, 5); let t := (q, r); q * 10 + t[1];",
+		&IntegerObject{Value: 32},
+	)
+
+	assertObject(t,
+		"[1, 2, 3][1];",
+		&IntegerObject{Value: 2},
+	)
+
+	assertObject(t,
+		"({ \"a\": 1, \"b\": 2 })[\"b\"];",
+		&IntegerObject{Value: 2},
+	)
+
+	assertObject(t,
+		"type point := struct { x: int; y: int; }; let p := point { x: 1, y: 2 }; p.x = 5; p.x * p.y;",
+		&IntegerObject{Value: 10},
+	)
+
+	assertObject(t,
+		"let n := 0; let inc := fn() int { n = n + 1; return n; }; inc(); inc(); n;",
+		&IntegerObject{Value: 2},
+	)
+
+	assertObject(t,
+		"let s := \"\"; for let i := 0; i < 3; i++ { for c in \"ab\" { s = s + c; } } for i in 0..2 { s = s + i; } s;",
+		&StringObject{Value: "ababab01"},
+	)
+
+	assertObject(t,
+		"let s := 0; outer: for i in 0..10 { for j in 0..10 { if j > i { continue outer; } if i == 4 { break outer; } s = s + 1; } } s;",
+		&IntegerObject{Value: 10},
+	)
+
+	assertObject(t,
+		"let name := \"anna\"; let xs := [1, 2]; \"${name}: ${xs} (${xs[0] + xs[1]}) \\${x}\";",
+		&StringObject{Value: "anna: [1, 2] (3) ${x}"},
+	)
+
+	assertObject(t,
+		"let s := \"\"; fn f(n: int) int { if n == 0 { throw \"zero\"; } return 10 / n; } "+
+			"try { f(0); } catch (e) { s = e.kind + \":\" + e.message; } finally { s += \"!\"; } "+
+			"try { s += \"\" + f(1) / 0; } catch (e) { s += e.kind; } s;",
+		&StringObject{Value: "Error:zero!RuntimeError"},
+	)
+
+	assertObject(t,
+		"let n := 0;\ntry {\n  throw \"x\";\n} catch (e) { n = (e.line ?? 0) * 100 + (e.col ?? 0); } n;",
+		&IntegerObject{Value: 303},
+	)
+
+	assertObject(t,
+		"let base := 10; fn f(a: int, b: int = base, c: int = base * 10) int { return a + b + c; } f(1) + f(1, c: 2) + f(b: 1, a: 2);",
+		&IntegerObject{Value: 227},
+	)
+
+	assertObject(t,
+		"fn sum(base: int = 0, xs: ...int) int { for x in xs { base += x; } return base; } "+
+			"let f: fn(int, int) int = sum; sum() + sum(1) + sum(1, 2, 3) + f(10, 20);",
+		&IntegerObject{Value: 37},
+	)
+
+	assertObject(t,
+		"`SELECT *\n  FROM \"users\" -- \\n ${x}`;",
+		&StringObject{Value: "SELECT *\n  FROM \"users\" -- \\n ${x}"},
+	)
+
+	assertObject(t,
+		"let fs: []fn() int = []; let xs := [0, 0, 0]; for let i := 0; i < 3; i++ { xs[i] = i; "+
+			"let f := fn() int { return i; }; if i == 0 { fs = [f]; } else { fs = [fs[0], f]; } } "+
+			"let a := 1; let t := (a, a); a++; fs[0]() * 1000 + fs[1]() * 100 + xs[1] * 10 + t[0] + a;",
+		&IntegerObject{Value: 213},
+	)
+
+	module := filepath.Join(t.TempDir(), "module.bs")
+	assert.NilError(t, os.WriteFile(module, []byte("let factor := 2; fn scale(n: int) int { return n * factor; }"), 0644))
+	assertObject(t,
+		"import \""+module+"\"; import \""+module+"\" as m; factor = 10; scale(1) + m.scale(5) + factor;",
+		&IntegerObject{Value: 22},
+	)
 }
 
 func assertObject(t *testing.T, input string, expected Object) {
@@ -50,6 +184,11 @@ func assertObject(t *testing.T, input string, expected Object) {
 			t.Error(err.Message)
 		}
 	} else {
-		assert.DeepEqual(t, Eval(program.Statements[0], environment), expected)
+		programEnvironment := ExtendEnvironment(environment, program.Context)
+		var result Object
+		for _, statement := range program.Statements {
+			result = Eval(statement, programEnvironment)
+		}
+		assert.DeepEqual(t, result, expected)
 	}
 }
@@ -14,7 +14,11 @@ type Object interface {
 }
 
 type ErrorObject struct {
+	Kind    string
 	Message string
+	File    *string
+	Line    int
+	Col     int
 }
 
 func (errorObject *ErrorObject) ToString() string {
@@ -25,6 +29,24 @@ func (*ErrorObject) Type() types.Type {
 	return nil
 }
 
+func (errorObject *ErrorObject) ToStruct() *StructObject {
+	fields := map[string]Object{
+		"kind":    &StringObject{Value: errorObject.Kind},
+		"message": &StringObject{Value: errorObject.Message},
+		"file":    &NullObject{},
+		"line":    &NullObject{},
+		"col":     &NullObject{},
+	}
+	if errorObject.File != nil {
+		fields["file"] = &StringObject{Value: *errorObject.File}
+	}
+	if errorObject.Line != 0 {
+		fields["line"] = &IntegerObject{Value: int64(errorObject.Line)}
+		fields["col"] = &IntegerObject{Value: int64(errorObject.Col)}
+	}
+	return &StructObject{Fields: fields, StructType: types.Error}
+}
+
 type ReturnObject struct {
 	Object Object
 }
@@ -37,6 +59,30 @@ func (returnObject *ReturnObject) Type() types.Type {
 	return returnObject.Object.Type()
 }
 
+type BreakObject struct {
+	Label string
+}
+
+func (*BreakObject) ToString() string {
+	return "break"
+}
+
+func (*BreakObject) Type() types.Type {
+	return nil
+}
+
+type ContinueObject struct {
+	Label string
+}
+
+func (*ContinueObject) ToString() string {
+	return "continue"
+}
+
+func (*ContinueObject) Type() types.Type {
+	return nil
+}
+
 type Function interface {
 	Object
 	Execute(arguments []Object) Object
@@ -45,7 +91,7 @@ type Function interface {
 
 type FunctionObject struct {
 	Environment  *Environment
-	Parameters   []*parser.Identifier
+	Parameters   []*parser.Parameter
 	Body         *parser.BlockStatement
 	This         Object
 	Context      *types.Context
@@ -57,8 +103,24 @@ func (functionObject *FunctionObject) Execute(arguments []Object) Object {
 	if functionObject.This != nil {
 		newEnvironment.DefineObject("this", functionObject.This)
 	}
-	for i, argument := range arguments {
-		name := functionObject.Parameters[i].Value
+	for i, parameter := range functionObject.Parameters {
+		var argument Object
+		if parameter.Variadic {
+			elements := make([]Object, 0)
+			if i < len(arguments) {
+				elements = append(elements, arguments[i:]...)
+			}
+			argument = &ArrayObject{Elements: elements, ElementType: parameter.Type}
+		} else if i < len(arguments) {
+			argument = arguments[i]
+		}
+		if argument == nil {
+			argument = Eval(parameter.Default, functionObject.Environment)
+			if isError(argument) {
+				return argument
+			}
+		}
+		name := parameter.Name.Value
 		_, ok := newEnvironment.DefineObject(name, argument)
 		if !ok {
 			return NewError("Parameter %s already exists", name)
@@ -81,6 +143,11 @@ func (*FunctionObject) ToString() string {
 	return "[Function]"
 }
 
+type Hashable interface {
+	Object
+	HashKey() interface{}
+}
+
 type StringObject struct {
 	Value string
 }
@@ -93,6 +160,10 @@ func (*StringObject) Type() types.Type {
 	return &types.String{}
 }
 
+func (stringObject *StringObject) HashKey() interface{} {
+	return stringObject.Value
+}
+
 type IntegerObject struct {
 	Value int64
 }
@@ -105,6 +176,10 @@ func (*IntegerObject) Type() types.Type {
 	return &types.Int{}
 }
 
+func (integerObject *IntegerObject) HashKey() interface{} {
+	return integerObject.Value
+}
+
 type FloatObject struct {
 	Value float64
 }
@@ -117,6 +192,10 @@ func (*FloatObject) Type() types.Type {
 	return &types.Float{}
 }
 
+func (floatObject *FloatObject) HashKey() interface{} {
+	return floatObject.Value
+}
+
 type BooleanObject struct {
 	Value bool
 }
@@ -129,6 +208,10 @@ func (*BooleanObject) Type() types.Type {
 	return &types.Bool{}
 }
 
+func (booleanObject *BooleanObject) HashKey() interface{} {
+	return booleanObject.Value
+}
+
 type NullObject struct {
 }
 
@@ -139,3 +222,141 @@ func (*NullObject) ToString() string {
 func (*NullObject) Type() types.Type {
 	return &types.Null{}
 }
+
+type ArrayObject struct {
+	Elements    []Object
+	ElementType types.Type
+}
+
+func (arrayObject *ArrayObject) ToString() string {
+	result := "["
+	for i, element := range arrayObject.Elements {
+		if i > 0 {
+			result += ", "
+		}
+		result += element.ToString()
+	}
+	return result + "]"
+}
+
+func (arrayObject *ArrayObject) Type() types.Type {
+	return &types.Array{ElementType: arrayObject.ElementType}
+}
+
+type TupleObject struct {
+	Elements []Object
+}
+
+func (tupleObject *TupleObject) ToString() string {
+	result := "("
+	for i, element := range tupleObject.Elements {
+		if i > 0 {
+			result += ", "
+		}
+		result += element.ToString()
+	}
+	return result + ")"
+}
+
+func (tupleObject *TupleObject) Type() types.Type {
+	elementTypes := make([]types.Type, len(tupleObject.Elements))
+	for i, element := range tupleObject.Elements {
+		elementTypes[i] = element.Type()
+	}
+	return &types.Tuple{ElementTypes: elementTypes}
+}
+
+type MapObject struct {
+	Keys      []Hashable
+	Values    map[interface{}]Object
+	KeyType   types.Type
+	ValueType types.Type
+}
+
+func NewMapObject(keyType types.Type, valueType types.Type) *MapObject {
+	return &MapObject{Keys: make([]Hashable, 0), Values: make(map[interface{}]Object), KeyType: keyType, ValueType: valueType}
+}
+
+func (mapObject *MapObject) Get(key Hashable) (Object, bool) {
+	value, ok := mapObject.Values[key.HashKey()]
+	return value, ok
+}
+
+func (mapObject *MapObject) Set(key Hashable, value Object) {
+	if _, exists := mapObject.Values[key.HashKey()]; !exists {
+		mapObject.Keys = append(mapObject.Keys, key)
+	}
+	mapObject.Values[key.HashKey()] = value
+}
+
+func (mapObject *MapObject) Delete(key Hashable) {
+	if _, exists := mapObject.Values[key.HashKey()]; !exists {
+		return
+	}
+	delete(mapObject.Values, key.HashKey())
+	for i, existingKey := range mapObject.Keys {
+		if existingKey.HashKey() == key.HashKey() {
+			mapObject.Keys = append(mapObject.Keys[:i], mapObject.Keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (mapObject *MapObject) ToString() string {
+	result := "{"
+	for i, key := range mapObject.Keys {
+		if i > 0 {
+			result += ", "
+		}
+		result += key.ToString() + ": " + mapObject.Values[key.HashKey()].ToString()
+	}
+	return result + "}"
+}
+
+func (mapObject *MapObject) Type() types.Type {
+	return &types.Map{KeyType: mapObject.KeyType, ValueType: mapObject.ValueType}
+}
+
+type StructObject struct {
+	Fields     map[string]Object
+	StructType *types.Struct
+}
+
+func (structObject *StructObject) ToString() string {
+	result := "{"
+	for i, name := range structObject.StructType.FieldNames {
+		if i > 0 {
+			result += ", "
+		}
+		result += name + ": " + structObject.Fields[name].ToString()
+	}
+	return result + "}"
+}
+
+func (structObject *StructObject) Type() types.Type {
+	return structObject.StructType
+}
+
+type EnumObject struct {
+	Variant  string
+	Values   []Object
+	EnumType *types.Enum
+}
+
+func (enumObject *EnumObject) ToString() string {
+	if len(enumObject.Values) == 0 {
+		return enumObject.Variant
+	}
+	result := enumObject.Variant + "("
+	for i, value := range enumObject.Values {
+		if i > 0 {
+			result += ", "
+		}
+		result += value.ToString()
+	}
+	return result + ")"
+}
+
+func (enumObject *EnumObject) Type() types.Type {
+	return enumObject.EnumType
+}
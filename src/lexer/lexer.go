@@ -16,6 +16,7 @@ type Lexer struct {
 	col            int
 	filePath       *string
 	lastWasIllegal bool
+	interpolations []int
 }
 
 func FromFile(fileName string) (*Lexer, error) {
@@ -78,18 +79,27 @@ func (lexer *Lexer) NextToken() *token.Token {
 		if lexer.current() == '=' {
 			lexer.consume()
 			return lexer.newToken(token.EQ, "", startCol)
+		} else if lexer.current() == '>' {
+			lexer.consume()
+			return lexer.newToken(token.Arrow, "", startCol)
 		}
 		return lexer.newToken(token.Assign, "", startCol)
 	case '+':
 		if lexer.current() == '+' {
 			lexer.consume()
 			return lexer.newToken(token.Increment, "", startCol)
+		} else if lexer.current() == '=' {
+			lexer.consume()
+			return lexer.newToken(token.PlusAssign, "", startCol)
 		}
 		return lexer.newToken(token.Plus, "", startCol)
 	case '-':
 		if lexer.current() == '-' {
 			lexer.consume()
 			return lexer.newToken(token.Decrement, "", startCol)
+		} else if lexer.current() == '=' {
+			lexer.consume()
+			return lexer.newToken(token.MinusAssign, "", startCol)
 		}
 		return lexer.newToken(token.Minus, "", startCol)
 	case '/':
@@ -99,23 +109,60 @@ func (lexer *Lexer) NextToken() *token.Token {
 		} else if lexer.current() == '*' {
 			lexer.eatComment()
 			return lexer.NextToken()
+		} else if lexer.current() == '=' {
+			lexer.consume()
+			return lexer.newToken(token.SlashAssign, "", startCol)
 		}
 		return lexer.newToken(token.Slash, "", startCol)
 	case '*':
+		if lexer.current() == '*' {
+			lexer.consume()
+			return lexer.newToken(token.Power, "", startCol)
+		} else if lexer.current() == '=' {
+			lexer.consume()
+			return lexer.newToken(token.StarAssign, "", startCol)
+		}
 		return lexer.newToken(token.Star, "", startCol)
+	case '%':
+		if lexer.current() == '=' {
+			lexer.consume()
+			return lexer.newToken(token.PercentAssign, "", startCol)
+		}
+		return lexer.newToken(token.Percent, "", startCol)
+	case '^':
+		return lexer.newToken(token.Caret, "", startCol)
+	case '~':
+		return lexer.newToken(token.Tilde, "", startCol)
 	case '<':
 		if lexer.current() == '=' {
 			lexer.consume()
 			return lexer.newToken(token.LTE, "", startCol)
+		} else if lexer.current() == '<' {
+			lexer.consume()
+			return lexer.newToken(token.ShiftLeft, "", startCol)
 		}
 		return lexer.newToken(token.LT, "", startCol)
 	case '>':
 		if lexer.current() == '=' {
 			lexer.consume()
 			return lexer.newToken(token.GTE, "", startCol)
+		} else if lexer.current() == '>' {
+			lexer.consume()
+			return lexer.newToken(token.ShiftRight, "", startCol)
 		}
 		return lexer.newToken(token.GT, "", startCol)
 	case '?':
+		if lexer.current() == '?' && lexer.peek() == '=' {
+			lexer.consume()
+			lexer.consume()
+			return lexer.newToken(token.NullishAssign, "", startCol)
+		} else if lexer.current() == '?' {
+			lexer.consume()
+			return lexer.newToken(token.Nullish, "", startCol)
+		} else if lexer.current() == '.' {
+			lexer.consume()
+			return lexer.newToken(token.QmarkDot, "", startCol)
+		}
 		return lexer.newToken(token.Qmark, "", startCol)
 	case '&':
 		if lexer.current() == '&' {
@@ -128,6 +175,7 @@ func (lexer *Lexer) NextToken() *token.Token {
 			lexer.consume()
 			return lexer.newToken(token.LogicalOr, "", startCol)
 		}
+		return lexer.newToken(token.Pipe, "", startCol)
 	case '!':
 		if lexer.current() == '=' {
 			lexer.consume()
@@ -135,6 +183,14 @@ func (lexer *Lexer) NextToken() *token.Token {
 		}
 		return lexer.newToken(token.Bang, "", startCol)
 	case '.':
+		if lexer.current() == '.' && lexer.peek() == '.' {
+			lexer.consume()
+			lexer.consume()
+			return lexer.newToken(token.Ellipsis, "", startCol)
+		} else if lexer.current() == '.' {
+			lexer.consume()
+			return lexer.newToken(token.DotDot, "", startCol)
+		}
 		return lexer.newToken(token.Dot, "", startCol)
 	case ',':
 		return lexer.newToken(token.Comma, "", startCol)
@@ -155,11 +211,27 @@ func (lexer *Lexer) NextToken() *token.Token {
 	case ')':
 		return lexer.newToken(token.RParen, "", startCol)
 	case '{':
+		if depth := len(lexer.interpolations); depth > 0 {
+			lexer.interpolations[depth-1]++
+		}
 		return lexer.newToken(token.LBrace, "", startCol)
 	case '}':
+		if depth := len(lexer.interpolations); depth > 0 {
+			if lexer.interpolations[depth-1] == 0 {
+				lexer.interpolations = lexer.interpolations[:depth-1]
+				return lexer.parseString(startCol, true)
+			}
+			lexer.interpolations[depth-1]--
+		}
 		return lexer.newToken(token.RBrace, "", startCol)
+	case '[':
+		return lexer.newToken(token.LBracket, "", startCol)
+	case ']':
+		return lexer.newToken(token.RBracket, "", startCol)
 	case '"':
-		return lexer.parseString(startCol)
+		return lexer.parseString(startCol, false)
+	case '`':
+		return lexer.parseRawString(startCol)
 	}
 
 	if isIdent(char) {
@@ -179,7 +251,7 @@ func (lexer *Lexer) NextToken() *token.Token {
 		for isDigit(lexer.current()) {
 			lexer.consume()
 		}
-		if lexer.current() == '.' {
+		if lexer.current() == '.' && lexer.peek() != '.' {
 			isFloat = true
 			lexer.consume()
 			for isDigit(lexer.current()) {
@@ -200,8 +272,12 @@ func (lexer *Lexer) NextToken() *token.Token {
 	}
 }
 
-func (lexer *Lexer) parseString(stringStartCol int) *token.Token {
+func (lexer *Lexer) parseString(stringStartCol int, continued bool) *token.Token {
 	literal := ""
+	endType, interpolationType := token.StringLiteral, token.StringHead
+	if continued {
+		endType, interpolationType = token.StringTail, token.StringMiddle
+	}
 
 parseChar:
 	for {
@@ -211,13 +287,20 @@ parseChar:
 			if current == '\n' || current == 0 {
 				lexer.error(startCol+1, "Unclosed string literal")
 			}
-			return lexer.newToken(token.StringLiteral, literal, stringStartCol)
+			return lexer.newToken(endType, literal, stringStartCol)
+		}
+		if current == '$' && lexer.current() == '{' {
+			lexer.consume()
+			lexer.interpolations = append(lexer.interpolations, 0)
+			return lexer.newToken(interpolationType, literal, stringStartCol)
 		}
 		toAdd := string(current)
 		if current == '\\' {
 			switch next := lexer.consume(); next {
 			case '\\':
 				toAdd = "\\"
+			case '$':
+				toAdd = "$"
 			case '"':
 				toAdd = "\""
 			case '\'':
@@ -300,6 +383,25 @@ parseChar:
 	}
 }
 
+func (lexer *Lexer) parseRawString(stringStartCol int) *token.Token {
+	startLine := lexer.line
+	literal := make([]rune, 0)
+	for {
+		current := lexer.consume()
+		if current == '`' {
+			break
+		}
+		if current == 0 {
+			lexer.errorAt(startLine, stringStartCol, "Unclosed string literal")
+			break
+		}
+		if current != '\r' {
+			literal = append(literal, current)
+		}
+	}
+	return lexer.newTokenAt(token.StringLiteral, string(literal), startLine, stringStartCol)
+}
+
 func (lexer *Lexer) eatWhitespace() {
 	for isWhitespace(lexer.current()) {
 		lexer.consume()
@@ -337,10 +439,19 @@ func isHex(char rune) bool {
 }
 
 func (lexer *Lexer) error(startCol int, messageFormat string, args ...interface{}) {
-	lexer.Errors = append(lexer.Errors, errors.New(lexer.line, startCol, lexer.filePath, messageFormat, args...))
+	lexer.errorAt(lexer.line, startCol, messageFormat, args...)
+}
+
+func (lexer *Lexer) errorAt(line int, startCol int, messageFormat string, args ...interface{}) {
+	lexer.Errors = append(lexer.Errors, errors.New(line, startCol, lexer.filePath, messageFormat, args...))
 }
 
 func (lexer *Lexer) newToken(tokenType token.Type, literal string, startCol int) *token.Token {
+	return lexer.newTokenAt(tokenType, literal, lexer.line, startCol)
+}
+
+// newTokenAt creates a token starting at the given line, which differs from the current line for multi-line tokens
+func (lexer *Lexer) newTokenAt(tokenType token.Type, literal string, line int, startCol int) *token.Token {
 	lexer.lastWasIllegal = tokenType == token.Illegal
-	return token.New(tokenType, literal, lexer.line, startCol, lexer.filePath)
+	return token.New(tokenType, literal, line, startCol, lexer.filePath)
 }
@@ -30,6 +30,80 @@ func TestLexer(t *testing.T) {
 			token.LBrace, token.Return, token.StringLiteral, token.Plus, token.Ident, token.Semi, token.RBrace},
 	)
 
+	assertTypes(t,
+		"xs[0] = [1, 2]",
+		[]token.Type{token.Ident, token.LBracket, token.IntLiteral, token.RBracket, token.Assign, token.LBracket,
+			token.IntLiteral, token.Comma, token.IntLiteral, token.RBracket},
+	)
+
+	assertTypes(t,
+		"for i in 0..10 {}",
+		[]token.Type{token.For, token.Ident, token.In, token.IntLiteral, token.DotDot, token.IntLiteral, token.LBrace,
+			token.RBrace},
+	)
+
+	assertTypes(t,
+		"fn(a: ...int) 0...1",
+		[]token.Type{token.Func, token.LParen, token.Ident, token.Colon, token.Ellipsis, token.Ident, token.RParen,
+			token.IntLiteral, token.Ellipsis, token.IntLiteral},
+	)
+
+	assertTypes(t,
+		"a % b ** c & d | e ^ ~f << g >> h",
+		[]token.Type{token.Ident, token.Percent, token.Ident, token.Power, token.Ident, token.Amp, token.Ident,
+			token.Pipe, token.Ident, token.Caret, token.Tilde, token.Ident, token.ShiftLeft, token.Ident,
+			token.ShiftRight, token.Ident},
+	)
+
+	assertTypes(t,
+		"a += b -= c *= d /= e %= f ??= g",
+		[]token.Type{token.Ident, token.PlusAssign, token.Ident, token.MinusAssign, token.Ident, token.StarAssign,
+			token.Ident, token.SlashAssign, token.Ident, token.PercentAssign, token.Ident, token.NullishAssign,
+			token.Ident},
+	)
+
+	assertTypes(t,
+		"a ?? b?.c ? d : e",
+		[]token.Type{token.Ident, token.Nullish, token.Ident, token.QmarkDot, token.Ident, token.Qmark, token.Ident,
+			token.Colon, token.Ident},
+	)
+
+	assertTypes(t,
+		"import \"lib/util.bs\" as util;",
+		[]token.Type{token.Import, token.StringLiteral, token.As, token.Ident, token.Semi},
+	)
+
+	assertTypes(t,
+		"try { throw x; } catch (e) { } finally { }",
+		[]token.Type{token.Try, token.LBrace, token.Throw, token.Ident, token.Semi, token.RBrace, token.Catch,
+			token.LParen, token.Ident, token.RParen, token.LBrace, token.RBrace, token.Finally, token.LBrace,
+			token.RBrace},
+	)
+
+	assertTypes(t,
+		"match x { is int => 1, _ => 2 }",
+		[]token.Type{token.Match, token.Ident, token.LBrace, token.Is, token.Ident, token.Arrow, token.IntLiteral,
+			token.Comma, token.Ident, token.Arrow, token.IntLiteral, token.RBrace},
+	)
+
+	assertTypes(t,
+		"\"a ${b + { \"c\": \"${d}\" }[e]} f ${g}\"",
+		[]token.Type{token.StringHead, token.Ident, token.Plus, token.LBrace, token.StringLiteral, token.Colon,
+			token.StringHead, token.Ident, token.StringTail, token.RBrace, token.LBracket, token.Ident, token.RBracket,
+			token.StringMiddle, token.Ident, token.StringTail},
+	)
+
+	assertToken(t,
+		"\"cost: \\${x} ${x}\"",
+		&token.Token{
+			Type:    token.StringHead,
+			Literal: "cost: ${x} ",
+			Line:    1,
+			Col:     1,
+			File:    nil,
+		},
+	)
+
 	assertToken(t,
 		"\"hello \\n \\\" \\t world\"",
 		&token.Token{
@@ -95,6 +169,21 @@ func TestLexer(t *testing.T) {
 			File:    nil,
 		},
 	)
+
+	assertToken(t,
+		"\n  `C:\\temp\\n\r\n${x}\"`",
+		&token.Token{
+			Type:    token.StringLiteral,
+			Literal: "C:\\temp\\n\n${x}\"",
+			Line:    2,
+			Col:     3,
+			File:    nil,
+		},
+	)
+
+	rawLexer := FromCode("`a\nbc` x")
+	rawLexer.NextToken()
+	assert.DeepEqual(t, rawLexer.NextToken(), &token.Token{Type: token.Ident, Literal: "x", Line: 2, Col: 5})
 }
 
 func assertTypes(t *testing.T, input string, expectedTypes []token.Type) {
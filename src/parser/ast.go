@@ -37,6 +37,11 @@ func (program *Program) ToString() string {
 	return result
 }
 
+type Module struct {
+	Path    string
+	Program *Program
+}
+
 type InvalidExpression struct {
 	InvalidToken *token.Token
 }
@@ -63,12 +68,20 @@ func (identifier *Identifier) ToString() string {
 }
 
 type Parameter struct {
-	Token *token.Token
-	Name  *Identifier
-	Type  types.Type
+	Token    *token.Token
+	Name     *Identifier
+	Type     types.Type
+	Default  Expression
+	Variadic bool
 }
 
 func (parameter *Parameter) ToString() string {
+	if parameter.Default != nil {
+		return parameter.Name.Value + ": " + parameter.Type.ToString() + " = " + parameter.Default.ToString()
+	}
+	if parameter.Variadic {
+		return parameter.Name.Value + ": ..." + parameter.Type.ToString()
+	}
 	return parameter.Name.Value + ": " + parameter.Type.ToString()
 }
 
@@ -115,6 +128,86 @@ func (infixExpression *InfixExpression) ToString() string {
 		infixExpression.Right.ToString() + ")"
 }
 
+type IsExpression struct {
+	IsToken    *token.Token
+	Expression Expression
+	Type       types.Type
+	Context    *types.Context
+}
+
+func (isExpression *IsExpression) Token() *token.Token {
+	return isExpression.Expression.Token()
+}
+
+func (isExpression *IsExpression) ToString() string {
+	return "(" + isExpression.Expression.ToString() + " is " + isExpression.Type.ToString() + ")"
+}
+
+type ConditionalExpression struct {
+	QmarkToken  *token.Token
+	ColonToken  *token.Token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (conditionalExpression *ConditionalExpression) Token() *token.Token {
+	return conditionalExpression.Condition.Token()
+}
+
+func (conditionalExpression *ConditionalExpression) ToString() string {
+	return "(" + conditionalExpression.Condition.ToString() + " ? " + conditionalExpression.Consequence.ToString() +
+		" : " + conditionalExpression.Alternative.ToString() + ")"
+}
+
+type MatchExpression struct {
+	MatchToken *token.Token
+	Subject    Expression
+	Arms       []*MatchArm
+	Exhaustive bool
+}
+
+func (matchExpression *MatchExpression) Token() *token.Token {
+	return matchExpression.MatchToken
+}
+
+func (matchExpression *MatchExpression) ToString() string {
+	result := "match " + matchExpression.Subject.ToString() + " {"
+	for i, arm := range matchExpression.Arms {
+		if i > 0 {
+			result += ","
+		}
+		result += " " + arm.ToString()
+	}
+	return result + " }"
+}
+
+type MatchArm struct {
+	ArrowToken  *token.Token
+	Patterns    []Expression
+	TypePattern types.Type
+	Wildcard    bool
+	Body        Statement
+	Context     *types.Context
+}
+
+func (matchArm *MatchArm) ToString() string {
+	result := ""
+	if matchArm.Wildcard {
+		result = "_"
+	} else if matchArm.TypePattern != nil {
+		result = "is " + matchArm.TypePattern.ToString()
+	} else {
+		for i, pattern := range matchArm.Patterns {
+			if i > 0 {
+				result += " | "
+			}
+			result += pattern.ToString()
+		}
+	}
+	return result + " => " + matchArm.Body.ToString()
+}
+
 type AssignmentExpression struct {
 	IdentToken  *token.Token
 	AssignToken *token.Token
@@ -127,13 +220,46 @@ func (assignmentExpression *AssignmentExpression) Token() *token.Token {
 }
 
 func (assignmentExpression *AssignmentExpression) ToString() string {
-	return "(" + assignmentExpression.Name.Value + " = " + assignmentExpression.Expression.ToString() + ")"
+	return "(" + assignmentExpression.Name.Value + " " + assignmentExpression.AssignToken.Type.ToString() + " " +
+		assignmentExpression.Expression.ToString() + ")"
+}
+
+type IndexAssignmentExpression struct {
+	AssignToken *token.Token
+	Index       *IndexExpression
+	Expression  Expression
+}
+
+func (indexAssignmentExpression *IndexAssignmentExpression) Token() *token.Token {
+	return indexAssignmentExpression.Index.Token()
+}
+
+func (indexAssignmentExpression *IndexAssignmentExpression) ToString() string {
+	return "(" + indexAssignmentExpression.Index.ToString() + " " + indexAssignmentExpression.AssignToken.Type.ToString() + " " +
+		indexAssignmentExpression.Expression.ToString() + ")"
+}
+
+type MemberAssignmentExpression struct {
+	AssignToken *token.Token
+	Member      *MemberAccessExpression
+	Expression  Expression
+}
+
+func (memberAssignmentExpression *MemberAssignmentExpression) Token() *token.Token {
+	return memberAssignmentExpression.Member.Token()
+}
+
+func (memberAssignmentExpression *MemberAssignmentExpression) ToString() string {
+	return "(" + memberAssignmentExpression.Member.ToString() + " " + memberAssignmentExpression.AssignToken.Type.ToString() + " " +
+		memberAssignmentExpression.Expression.ToString() + ")"
 }
 
 type CallExpression struct {
-	ParenToken *token.Token
-	Function   Expression
-	Arguments  []Expression
+	ParenToken       *token.Token
+	Function         Expression
+	Arguments        []Expression
+	ArgumentNames    []*Identifier
+	ParameterIndices []int
 }
 
 func (callExpression *CallExpression) Token() *token.Token {
@@ -146,6 +272,9 @@ func (callExpression *CallExpression) ToString() string {
 		if i > 0 {
 			expressionString += ", "
 		}
+		if callExpression.ArgumentNames != nil && callExpression.ArgumentNames[i] != nil {
+			expressionString += callExpression.ArgumentNames[i].Value + ": "
+		}
 		expressionString += argument.ToString()
 	}
 	return expressionString + "))"
@@ -164,6 +293,27 @@ func (stringLiteral *StringLiteral) ToString() string {
 	return stringLiteral.Value
 }
 
+type InterpolatedStringLiteral struct {
+	HeadToken *token.Token
+	Parts     []Expression
+}
+
+func (interpolatedStringLiteral *InterpolatedStringLiteral) Token() *token.Token {
+	return interpolatedStringLiteral.HeadToken
+}
+
+func (interpolatedStringLiteral *InterpolatedStringLiteral) ToString() string {
+	result := ""
+	for _, part := range interpolatedStringLiteral.Parts {
+		if stringLiteral, isString := part.(*StringLiteral); isString {
+			result += stringLiteral.Value
+		} else {
+			result += "${" + part.ToString() + "}"
+		}
+	}
+	return result
+}
+
 type IntegerLiteral struct {
 	LiteralToken *token.Token
 	Value        int64
@@ -230,6 +380,7 @@ func (voidLiteral *VoidLiteral) ToString() string {
 type FunctionDefinitionStatement struct {
 	FuncToken       *token.Token
 	Name            *Identifier
+	TypeParameters  []*types.TypeParameter
 	Parameters      []*Parameter
 	Body            *BlockStatement
 	FunctionContext *types.Context
@@ -243,7 +394,7 @@ func (funcStatement *FunctionDefinitionStatement) Token() *token.Token {
 }
 
 func (funcStatement *FunctionDefinitionStatement) ToString() string {
-	result := "fn " + funcStatement.Name.Value + "("
+	result := "fn " + funcStatement.Name.Value + typeParametersToString(funcStatement.TypeParameters) + "("
 	for i, parameter := range funcStatement.Parameters {
 		if i > 0 {
 			result += ", "
@@ -253,11 +404,54 @@ func (funcStatement *FunctionDefinitionStatement) ToString() string {
 	return result + funcStatement.Body.ToString()
 }
 
+type FunctionLiteral struct {
+	FuncToken       *token.Token
+	TypeParameters  []*types.TypeParameter
+	Parameters      []*Parameter
+	Body            *BlockStatement
+	FunctionContext *types.Context
+	ReturnType      types.Type
+	FunctionType    *types.Function
+}
+
+func (functionLiteral *FunctionLiteral) Token() *token.Token {
+	return functionLiteral.FuncToken
+}
+
+func (functionLiteral *FunctionLiteral) ToString() string {
+	result := "fn" + typeParametersToString(functionLiteral.TypeParameters) + "("
+	for i, parameter := range functionLiteral.Parameters {
+		if i > 0 {
+			result += ", "
+		}
+		result += parameter.ToString()
+	}
+	return result + ") " + functionLiteral.ReturnType.ToString() + " " + functionLiteral.Body.ToString()
+}
+
+func typeParametersToString(typeParameters []*types.TypeParameter) string {
+	if len(typeParameters) == 0 {
+		return ""
+	}
+	result := "<"
+	for i, typeParameter := range typeParameters {
+		if i > 0 {
+			result += ", "
+		}
+		result += typeParameter.Name
+		if typeParameter.Constraint != nil {
+			result += ": " + typeParameter.Constraint.ToString()
+		}
+	}
+	return result + ">"
+}
+
 type LetStatement struct {
 	LetToken *token.Token
 	Name     *Identifier
 	Type     types.Type
 	Value    Expression
+	Constant bool
 }
 
 func (letStatement *LetStatement) Token() *token.Token {
@@ -265,7 +459,40 @@ func (letStatement *LetStatement) Token() *token.Token {
 }
 
 func (letStatement *LetStatement) ToString() string {
-	return fmt.Sprintf("let %s: %s = %s;", letStatement.Name.Value, letStatement.Type.ToString(), letStatement.Value.ToString())
+	keyword := "let"
+	if letStatement.Constant {
+		keyword = "const"
+	}
+	return fmt.Sprintf("%s %s: %s = %s;", keyword, letStatement.Name.Value, letStatement.Type.ToString(),
+		letStatement.Value.ToString())
+}
+
+type DestructuringStatement struct {
+	LetToken *token.Token
+	Names    []*Identifier
+	Type     types.Type
+	Value    Expression
+	Constant bool
+}
+
+func (destructuringStatement *DestructuringStatement) Token() *token.Token {
+	return destructuringStatement.LetToken
+}
+
+func (destructuringStatement *DestructuringStatement) ToString() string {
+	keyword := "let"
+	if destructuringStatement.Constant {
+		keyword = "const"
+	}
+	names := ""
+	for i, name := range destructuringStatement.Names {
+		if i > 0 {
+			names += ", "
+		}
+		names += name.Value
+	}
+	return fmt.Sprintf("%s (%s): %s = %s;", keyword, names, destructuringStatement.Type.ToString(),
+		destructuringStatement.Value.ToString())
 }
 
 type ReturnStatement struct {
@@ -321,8 +548,21 @@ func (ifStatement *IfStatement) ToString() string {
 	return result
 }
 
+type MatchStatement struct {
+	Match *MatchExpression
+}
+
+func (matchStatement *MatchStatement) Token() *token.Token {
+	return matchStatement.Match.Token()
+}
+
+func (matchStatement *MatchStatement) ToString() string {
+	return matchStatement.Match.ToString()
+}
+
 type WhileStatement struct {
 	WhileToken       *token.Token
+	Label            *Identifier
 	Condition        Expression
 	Statement        Statement
 	StatementContext *types.Context
@@ -336,6 +576,149 @@ func (whileStatement *WhileStatement) ToString() string {
 	return "while " + whileStatement.Condition.ToString() + " " + whileStatement.Statement.ToString()
 }
 
+type ForStatement struct {
+	ForToken         *token.Token
+	Label            *Identifier
+	Initializer      Statement
+	Condition        Expression
+	Update           Expression
+	Statement        Statement
+	LoopContext      *types.Context
+	StatementContext *types.Context
+}
+
+func (forStatement *ForStatement) Token() *token.Token {
+	return forStatement.ForToken
+}
+
+func (forStatement *ForStatement) ToString() string {
+	result := "for "
+	if forStatement.Initializer != nil {
+		result += forStatement.Initializer.ToString()
+	} else {
+		result += ";"
+	}
+	if forStatement.Condition != nil {
+		result += " " + forStatement.Condition.ToString()
+	}
+	result += ";"
+	if forStatement.Update != nil {
+		result += " " + forStatement.Update.ToString()
+	}
+	return result + " " + forStatement.Statement.ToString()
+}
+
+type ForInStatement struct {
+	ForToken         *token.Token
+	Label            *Identifier
+	Variable         *Identifier
+	Iterable         Expression
+	Statement        Statement
+	StatementContext *types.Context
+}
+
+func (forInStatement *ForInStatement) Token() *token.Token {
+	return forInStatement.ForToken
+}
+
+func (forInStatement *ForInStatement) ToString() string {
+	return "for " + forInStatement.Variable.Value + " in " + forInStatement.Iterable.ToString() + " " +
+		forInStatement.Statement.ToString()
+}
+
+type BreakStatement struct {
+	BreakToken *token.Token
+	Label      *Identifier
+}
+
+func (breakStatement *BreakStatement) Token() *token.Token {
+	return breakStatement.BreakToken
+}
+
+func (breakStatement *BreakStatement) ToString() string {
+	if breakStatement.Label != nil {
+		return "break " + breakStatement.Label.Value + ";"
+	}
+	return "break;"
+}
+
+type ContinueStatement struct {
+	ContinueToken *token.Token
+	Label         *Identifier
+}
+
+func (continueStatement *ContinueStatement) Token() *token.Token {
+	return continueStatement.ContinueToken
+}
+
+func (continueStatement *ContinueStatement) ToString() string {
+	if continueStatement.Label != nil {
+		return "continue " + continueStatement.Label.Value + ";"
+	}
+	return "continue;"
+}
+
+type ImportStatement struct {
+	ImportToken *token.Token
+	Path        *StringLiteral
+	Alias       *Identifier
+	AliasType   *types.Struct
+	Module      *Module
+}
+
+func (importStatement *ImportStatement) Token() *token.Token {
+	return importStatement.ImportToken
+}
+
+func (importStatement *ImportStatement) ToString() string {
+	result := "import \"" + importStatement.Path.Value + "\""
+	if importStatement.Alias != nil {
+		result += " as " + importStatement.Alias.Value
+	}
+	return result + ";"
+}
+
+type ThrowStatement struct {
+	ThrowToken *token.Token
+	Value      Expression
+}
+
+func (throwStatement *ThrowStatement) Token() *token.Token {
+	return throwStatement.ThrowToken
+}
+
+func (throwStatement *ThrowStatement) ToString() string {
+	return "throw " + throwStatement.Value.ToString() + ";"
+}
+
+type TryStatement struct {
+	TryToken      *token.Token
+	Body          *BlockStatement
+	CatchVariable *Identifier
+	CatchContext  *types.Context
+	Catch         *BlockStatement
+	Finally       *BlockStatement
+}
+
+func (tryStatement *TryStatement) Token() *token.Token {
+	return tryStatement.TryToken
+}
+
+func (tryStatement *TryStatement) ToString() string {
+	result := "try " + tryStatement.Body.ToString()
+	if tryStatement.Catch != nil {
+		result += " catch "
+		if tryStatement.CatchVariable != nil {
+			result += "(" + tryStatement.CatchVariable.Value + ") "
+		}
+		result += tryStatement.Catch.ToString()
+	}
+	if tryStatement.Finally != nil {
+		result += " finally " + tryStatement.Finally.ToString()
+	}
+	return result
+}
+
 type IncrementExpression struct {
 	OperatorToken *token.Token
 	Operator      token.Type
@@ -365,6 +748,7 @@ type MemberAccessExpression struct {
 	Member     *Identifier
 	ParentType types.Type
 	MemberType types.Type
+	Optional   bool
 }
 
 func (memberAccessExpression *MemberAccessExpression) Token() *token.Token {
@@ -372,6 +756,9 @@ func (memberAccessExpression *MemberAccessExpression) Token() *token.Token {
 }
 
 func (memberAccessExpression *MemberAccessExpression) ToString() string {
+	if memberAccessExpression.Optional {
+		return memberAccessExpression.Expression.ToString() + "?." + memberAccessExpression.Member.Value
+	}
 	return memberAccessExpression.Expression.ToString() + "." + memberAccessExpression.Member.Value
 }
 
@@ -388,3 +775,156 @@ func (typeDefinitionStatement *TypeDefinitionStatement) Token() *token.Token {
 func (typeDefinitionStatement *TypeDefinitionStatement) ToString() string {
 	return fmt.Sprintf("type %s := %s;", typeDefinitionStatement.Name.Value, typeDefinitionStatement.Type.ToString())
 }
+
+type ArrayLiteral struct {
+	LBracketToken *token.Token
+	Elements      []Expression
+	Type          *types.Array
+}
+
+func (arrayLiteral *ArrayLiteral) Token() *token.Token {
+	return arrayLiteral.LBracketToken
+}
+
+func (arrayLiteral *ArrayLiteral) ToString() string {
+	result := "["
+	for i, element := range arrayLiteral.Elements {
+		if i > 0 {
+			result += ", "
+		}
+		result += element.ToString()
+	}
+	return result + "]"
+}
+
+type MapLiteral struct {
+	LBraceToken *token.Token
+	Keys        []Expression
+	Values      []Expression
+	Type        *types.Map
+}
+
+func (mapLiteral *MapLiteral) Token() *token.Token {
+	return mapLiteral.LBraceToken
+}
+
+func (mapLiteral *MapLiteral) ToString() string {
+	result := "{"
+	for i, key := range mapLiteral.Keys {
+		if i > 0 {
+			result += ", "
+		}
+		result += key.ToString() + ": " + mapLiteral.Values[i].ToString()
+	}
+	return result + "}"
+}
+
+type IndexExpression struct {
+	LBracketToken *token.Token
+	Expression    Expression
+	Index         Expression
+}
+
+func (indexExpression *IndexExpression) Token() *token.Token {
+	return indexExpression.LBracketToken
+}
+
+func (indexExpression *IndexExpression) ToString() string {
+	return "(" + indexExpression.Expression.ToString() + "[" + indexExpression.Index.ToString() + "])"
+}
+
+type TupleLiteral struct {
+	LParenToken *token.Token
+	Elements    []Expression
+}
+
+func (tupleLiteral *TupleLiteral) Token() *token.Token {
+	return tupleLiteral.LParenToken
+}
+
+func (tupleLiteral *TupleLiteral) ToString() string {
+	result := "("
+	for i, element := range tupleLiteral.Elements {
+		if i > 0 {
+			result += ", "
+		}
+		result += element.ToString()
+	}
+	return result + ")"
+}
+
+type StructLiteral struct {
+	TypeName   *Identifier
+	Type       *types.Struct
+	FieldNames []*Identifier
+	Values     []Expression
+}
+
+func (structLiteral *StructLiteral) Token() *token.Token {
+	return structLiteral.TypeName.IdentToken
+}
+
+func (structLiteral *StructLiteral) ToString() string {
+	result := structLiteral.TypeName.Value + " {"
+	for i, fieldName := range structLiteral.FieldNames {
+		if i > 0 {
+			result += ","
+		}
+		result += " " + fieldName.Value + ": " + structLiteral.Values[i].ToString()
+	}
+	return result + " }"
+}
+
+type EnumLiteral struct {
+	TypeName  *Identifier
+	Type      *types.Enum
+	Variant   *Identifier
+	Arguments []Expression
+}
+
+func (enumLiteral *EnumLiteral) Token() *token.Token {
+	return enumLiteral.Variant.IdentToken
+}
+
+func (enumLiteral *EnumLiteral) ToString() string {
+	result := enumLiteral.TypeName.Value + "." + enumLiteral.Variant.Value
+	if len(enumLiteral.Arguments) == 0 {
+		return result
+	}
+	for i, argument := range enumLiteral.Arguments {
+		if i == 0 {
+			result += "("
+		} else {
+			result += ", "
+		}
+		result += argument.ToString()
+	}
+	return result + ")"
+}
+
+type EnumPattern struct {
+	TypeName *Identifier
+	Type     *types.Enum
+	Variant  *Identifier
+	Bindings []*Identifier
+}
+
+func (enumPattern *EnumPattern) Token() *token.Token {
+	return enumPattern.Variant.IdentToken
+}
+
+func (enumPattern *EnumPattern) ToString() string {
+	result := enumPattern.TypeName.Value + "." + enumPattern.Variant.Value
+	if len(enumPattern.Bindings) == 0 {
+		return result
+	}
+	for i, binding := range enumPattern.Bindings {
+		if i == 0 {
+			result += "("
+		} else {
+			result += ", "
+		}
+		result += binding.Value
+	}
+	return result + ")"
+}
@@ -11,38 +11,62 @@ type ExpressionPrecedence int
 const (
 	ExpressionLowest ExpressionPrecedence = iota
 	ExpressionAssignment
+	ExpressionConditional
+	ExpressionNullish
 	ExpressionLogicalOr
 	ExpressionLogicalAnd
 	ExpressionEquals
 	ExpressionRelation
+	ExpressionRange
+	ExpressionBitwiseOr
+	ExpressionBitwiseXor
+	ExpressionBitwiseAnd
+	ExpressionShift
 	ExpressionSum
 	ExpressionProduct
 	ExpressionPrefix
+	ExpressionPower
 	ExpressionPostfix
 )
 
 var expressionPrecedences = map[token.Type]ExpressionPrecedence{
-	token.Assign:     ExpressionAssignment,
-	token.LogicalOr:  ExpressionLogicalOr,
-	token.LogicalAnd: ExpressionLogicalAnd,
-	token.EQ:         ExpressionEquals,
-	token.NEQ:        ExpressionEquals,
-	token.LT:         ExpressionRelation,
-	token.GT:         ExpressionRelation,
-	token.LTE:        ExpressionRelation,
-	token.GTE:        ExpressionRelation,
-	token.Plus:       ExpressionSum,
-	token.Minus:      ExpressionSum,
-	token.Slash:      ExpressionProduct,
-	token.Star:       ExpressionProduct,
-	token.Increment:  ExpressionPostfix,
-	token.Decrement:  ExpressionPostfix,
-	token.LParen:     ExpressionPostfix,
-	token.Dot:        ExpressionPostfix,
-}
-
-var prefixExpressionParseFunctions = make(map[token.Type]func(*types.Context) Expression)
-var infixExpressionParseFunctions = make(map[token.Type]func(*types.Context, Expression) Expression)
+	token.Assign:        ExpressionAssignment,
+	token.PlusAssign:    ExpressionAssignment,
+	token.MinusAssign:   ExpressionAssignment,
+	token.StarAssign:    ExpressionAssignment,
+	token.SlashAssign:   ExpressionAssignment,
+	token.PercentAssign: ExpressionAssignment,
+	token.NullishAssign: ExpressionAssignment,
+	token.Qmark:         ExpressionConditional,
+	token.Nullish:       ExpressionNullish,
+	token.LogicalOr:     ExpressionLogicalOr,
+	token.LogicalAnd:    ExpressionLogicalAnd,
+	token.EQ:            ExpressionEquals,
+	token.NEQ:           ExpressionEquals,
+	token.LT:            ExpressionRelation,
+	token.GT:            ExpressionRelation,
+	token.LTE:           ExpressionRelation,
+	token.GTE:           ExpressionRelation,
+	token.Is:            ExpressionRelation,
+	token.DotDot:        ExpressionRange,
+	token.Pipe:          ExpressionBitwiseOr,
+	token.Caret:         ExpressionBitwiseXor,
+	token.Amp:           ExpressionBitwiseAnd,
+	token.ShiftLeft:     ExpressionShift,
+	token.ShiftRight:    ExpressionShift,
+	token.Plus:          ExpressionSum,
+	token.Minus:         ExpressionSum,
+	token.Slash:         ExpressionProduct,
+	token.Star:          ExpressionProduct,
+	token.Percent:       ExpressionProduct,
+	token.Power:         ExpressionPower,
+	token.Increment:     ExpressionPostfix,
+	token.Decrement:     ExpressionPostfix,
+	token.LParen:        ExpressionPostfix,
+	token.Dot:           ExpressionPostfix,
+	token.QmarkDot:      ExpressionPostfix,
+	token.LBracket:      ExpressionPostfix,
+}
 
 func getExpressionPrecedence(token *token.Token) ExpressionPrecedence {
 	if precedence, exists := expressionPrecedences[token.Type]; exists {
@@ -52,44 +76,73 @@ func getExpressionPrecedence(token *token.Token) ExpressionPrecedence {
 }
 
 func (parser *Parser) registerExpressionParseFunctions() {
-	prefixExpressionParseFunctions[token.Ident] = parser.parseIdentifier
-	prefixExpressionParseFunctions[token.IntLiteral] = parser.parseIntegerLiteral
-	prefixExpressionParseFunctions[token.FloatLiteral] = parser.parseFloatLiteral
-	prefixExpressionParseFunctions[token.StringLiteral] = parser.parseStringLiteral
-	prefixExpressionParseFunctions[token.Null] = parser.parseNullLiteral
-	prefixExpressionParseFunctions[token.Void] = parser.parseVoidLiteral
-	prefixExpressionParseFunctions[token.True] = parser.parseBooleanLiteral
-	prefixExpressionParseFunctions[token.False] = parser.parseBooleanLiteral
-	prefixExpressionParseFunctions[token.Bang] = parser.parsePrefixExpression
-	prefixExpressionParseFunctions[token.Minus] = parser.parsePrefixExpression
-	prefixExpressionParseFunctions[token.LParen] = parser.parseGroupedExpression
-	prefixExpressionParseFunctions[token.Increment] = parser.parseIncrementPrefixExpression
-	prefixExpressionParseFunctions[token.Decrement] = parser.parseIncrementPrefixExpression
-
-	infixExpressionParseFunctions[token.Assign] = parser.parseAssignmentExpression
-	infixExpressionParseFunctions[token.LogicalOr] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.LogicalAnd] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.EQ] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.NEQ] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.GT] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.LT] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.GTE] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.LTE] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.Plus] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.Minus] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.Slash] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.Star] = parser.parseInfixExpression
-	infixExpressionParseFunctions[token.LParen] = parser.parseCallExpression
-	infixExpressionParseFunctions[token.Increment] = parser.parseIncrementInfixExpression
-	infixExpressionParseFunctions[token.Decrement] = parser.parseIncrementInfixExpression
-	infixExpressionParseFunctions[token.Dot] = parser.parseMemberAccessExpression
+	parser.prefixExpressionParseFunctions[token.Ident] = parser.parseIdentifier
+	parser.prefixExpressionParseFunctions[token.IntLiteral] = parser.parseIntegerLiteral
+	parser.prefixExpressionParseFunctions[token.FloatLiteral] = parser.parseFloatLiteral
+	parser.prefixExpressionParseFunctions[token.StringLiteral] = parser.parseStringLiteral
+	parser.prefixExpressionParseFunctions[token.StringHead] = parser.parseInterpolatedStringLiteral
+	parser.prefixExpressionParseFunctions[token.Null] = parser.parseNullLiteral
+	parser.prefixExpressionParseFunctions[token.Void] = parser.parseVoidLiteral
+	parser.prefixExpressionParseFunctions[token.True] = parser.parseBooleanLiteral
+	parser.prefixExpressionParseFunctions[token.False] = parser.parseBooleanLiteral
+	parser.prefixExpressionParseFunctions[token.Bang] = parser.parsePrefixExpression
+	parser.prefixExpressionParseFunctions[token.Minus] = parser.parsePrefixExpression
+	parser.prefixExpressionParseFunctions[token.Tilde] = parser.parsePrefixExpression
+	parser.prefixExpressionParseFunctions[token.Match] = parser.parseMatchExpression
+	parser.prefixExpressionParseFunctions[token.LParen] = parser.parseGroupedExpression
+	parser.prefixExpressionParseFunctions[token.Increment] = parser.parseIncrementPrefixExpression
+	parser.prefixExpressionParseFunctions[token.Decrement] = parser.parseIncrementPrefixExpression
+	parser.prefixExpressionParseFunctions[token.LBracket] = parser.parseArrayLiteral
+	parser.prefixExpressionParseFunctions[token.LBrace] = parser.parseMapLiteral
+	parser.prefixExpressionParseFunctions[token.Func] = parser.parseFunctionLiteral
+
+	parser.infixExpressionParseFunctions[token.Assign] = parser.parseAssignmentExpression
+	parser.infixExpressionParseFunctions[token.PlusAssign] = parser.parseAssignmentExpression
+	parser.infixExpressionParseFunctions[token.MinusAssign] = parser.parseAssignmentExpression
+	parser.infixExpressionParseFunctions[token.StarAssign] = parser.parseAssignmentExpression
+	parser.infixExpressionParseFunctions[token.SlashAssign] = parser.parseAssignmentExpression
+	parser.infixExpressionParseFunctions[token.PercentAssign] = parser.parseAssignmentExpression
+	parser.infixExpressionParseFunctions[token.NullishAssign] = parser.parseAssignmentExpression
+	parser.infixExpressionParseFunctions[token.Qmark] = parser.parseConditionalExpression
+	parser.infixExpressionParseFunctions[token.Is] = parser.parseIsExpression
+	parser.infixExpressionParseFunctions[token.Nullish] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.LogicalOr] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.LogicalAnd] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.EQ] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.NEQ] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.GT] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.LT] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.GTE] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.LTE] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.DotDot] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Plus] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Minus] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Slash] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Star] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Percent] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Power] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Pipe] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Caret] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.Amp] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.ShiftLeft] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.ShiftRight] = parser.parseInfixExpression
+	parser.infixExpressionParseFunctions[token.LParen] = parser.parseCallExpression
+	parser.infixExpressionParseFunctions[token.Increment] = parser.parseIncrementInfixExpression
+	parser.infixExpressionParseFunctions[token.Decrement] = parser.parseIncrementInfixExpression
+	parser.infixExpressionParseFunctions[token.Dot] = parser.parseMemberAccessExpression
+	parser.infixExpressionParseFunctions[token.QmarkDot] = parser.parseMemberAccessExpression
+	parser.infixExpressionParseFunctions[token.LBracket] = parser.parseIndexExpression
 }
 
 func (parser *Parser) parseExpression(context *types.Context, precedence ExpressionPrecedence) Expression {
 	currentToken := parser.current()
-	prefixFunction := prefixExpressionParseFunctions[currentToken.Type]
+	prefixFunction := parser.prefixExpressionParseFunctions[currentToken.Type]
 	if prefixFunction == nil {
-		if currentToken.Type != token.Illegal {
+		switch currentToken.Type {
+		case token.Illegal:
+		case token.StringMiddle, token.StringTail:
+			parser.error(currentToken, "Unexpected '}'")
+		default:
 			parser.error(currentToken, "Unexpected %s", currentToken.ToString())
 		}
 		return &InvalidExpression{currentToken}
@@ -98,7 +151,7 @@ func (parser *Parser) parseExpression(context *types.Context, precedence Express
 	expression := prefixFunction(context)
 
 	for parser.peek().Type != token.Semi && precedence < getExpressionPrecedence(parser.peek()) {
-		infixFunction := infixExpressionParseFunctions[parser.peek().Type]
+		infixFunction := parser.infixExpressionParseFunctions[parser.peek().Type]
 		if infixFunction == nil {
 			break
 		}
@@ -121,8 +174,46 @@ func (parser *Parser) parsePrefixExpression(context *types.Context) Expression {
 	}
 }
 
-func (parser *Parser) parseIdentifier(*types.Context) Expression {
-	return &Identifier{IdentToken: parser.current(), Value: parser.current().Literal}
+func (parser *Parser) parseIdentifier(context *types.Context) Expression {
+	identToken := parser.current()
+	identifier := &Identifier{IdentToken: identToken, Value: identToken.Literal}
+
+	theType, isType := context.GetType(identifier.Value)
+	if _, isMember := context.GetMemberType(identifier.Value); !isType || isMember {
+		return identifier
+	}
+
+	if generic, isGeneric := theType.(*types.Generic); isGeneric && parser.peek().Type == token.LT {
+		switch generic.Type.(type) {
+		case *types.Struct:
+			theType = parser.parseGenericTypeInstance(context, generic)
+			if isNever(theType) {
+				return &InvalidExpression{identToken}
+			}
+			if parser.peek().Type != token.LBrace {
+				parser.assertNext(token.LBrace)
+				return &InvalidExpression{identToken}
+			}
+		case *types.Enum:
+			theType = parser.parseGenericTypeInstance(context, generic)
+			if isNever(theType) {
+				return &InvalidExpression{identToken}
+			}
+			if parser.peek().Type != token.Dot {
+				parser.assertNext(token.Dot)
+				return &InvalidExpression{identToken}
+			}
+		}
+	}
+
+	if structType, isStruct := theType.(*types.Struct); isStruct && parser.peek().Type == token.LBrace {
+		return parser.parseStructLiteral(context, identifier, structType)
+	}
+	if enumType, isEnum := theType.(*types.Enum); isEnum && parser.peek().Type == token.Dot {
+		return parser.parseEnumLiteral(context, identifier, enumType)
+	}
+
+	return identifier
 }
 
 func (parser *Parser) parseStringLiteral(*types.Context) Expression {
@@ -130,6 +221,40 @@ func (parser *Parser) parseStringLiteral(*types.Context) Expression {
 	return &StringLiteral{LiteralToken: currentToken, Value: currentToken.Literal}
 }
 
+func (parser *Parser) parseInterpolatedStringLiteral(context *types.Context) Expression {
+	headToken := parser.current()
+	literal := &InterpolatedStringLiteral{HeadToken: headToken}
+	literal.Parts = append(literal.Parts, &StringLiteral{LiteralToken: headToken, Value: headToken.Literal})
+
+	for {
+		parser.consume()
+		if currentType := parser.current().Type; currentType == token.StringMiddle || currentType == token.StringTail {
+			parser.error(parser.current(), "Empty interpolation")
+			return &InvalidExpression{parser.current()}
+		}
+		errorCount := len(parser.errors)
+		expression := parser.parseExpression(context, ExpressionLowest)
+		if isInvalid(expression) {
+			return expression
+		}
+		literal.Parts = append(literal.Parts, expression)
+
+		nextType := parser.peek().Type
+		if nextType != token.StringMiddle && nextType != token.StringTail {
+			if len(parser.errors) == errorCount { // the expression may already have consumed the '}'
+				parser.assertNext(token.RBrace)
+			}
+			return &InvalidExpression{parser.current()}
+		}
+		parser.consume()
+		partToken := parser.current()
+		literal.Parts = append(literal.Parts, &StringLiteral{LiteralToken: partToken, Value: partToken.Literal})
+		if nextType == token.StringTail {
+			return literal
+		}
+	}
+}
+
 func (parser *Parser) parseBooleanLiteral(*types.Context) Expression {
 	currentToken := parser.current()
 	return &BooleanLiteral{LiteralToken: currentToken, Value: currentToken.Type == token.True}
@@ -172,8 +297,22 @@ func (parser *Parser) parseFloatLiteral(*types.Context) Expression {
 }
 
 func (parser *Parser) parseGroupedExpression(context *types.Context) Expression {
-	parser.consume()
+	lParenToken := parser.consume()
 	expression := parser.parseExpression(context, ExpressionLowest)
+	if parser.peek().Type == token.Comma {
+		tupleLiteral := &TupleLiteral{LParenToken: lParenToken, Elements: []Expression{expression}}
+		for parser.peek().Type == token.Comma {
+			parser.consume()
+			parser.consume()
+			tupleLiteral.Elements = append(tupleLiteral.Elements, parser.parseExpression(context, ExpressionLowest))
+		}
+		for _, element := range tupleLiteral.Elements {
+			if isInvalid(element) {
+				return element
+			}
+		}
+		expression = tupleLiteral
+	}
 	if !parser.assertNext(token.RParen) {
 		return &InvalidExpression{parser.current()}
 	}
@@ -186,19 +325,358 @@ func (parser *Parser) parseIncrementPrefixExpression(context *types.Context) Exp
 	return parser.parseIncrementExpression(operatorToken, identExpression, true)
 }
 
+func (parser *Parser) parseArrayLiteral(context *types.Context) Expression {
+	lBracketToken := parser.current()
+	elements := parser.parseExpressionList(context, token.RBracket)
+
+	if elements == nil {
+		return &InvalidExpression{lBracketToken}
+	}
+
+	for _, element := range elements {
+		if isInvalid(element) {
+			return element
+		}
+	}
+
+	return &ArrayLiteral{
+		LBracketToken: lBracketToken,
+		Elements:      elements,
+	}
+}
+
+func (parser *Parser) parseFunctionLiteral(context *types.Context) Expression {
+	literal := &FunctionLiteral{FuncToken: parser.current()}
+
+	signatureContext := context
+	if parser.peek().Type == token.LT {
+		parser.consume()
+		literal.TypeParameters, signatureContext = parser.parseTypeParameterList(context)
+		if literal.TypeParameters == nil {
+			return &InvalidExpression{literal.FuncToken}
+		}
+	}
+
+	if !parser.assertNext(token.LParen) {
+		return &InvalidExpression{literal.FuncToken}
+	}
+
+	var ok bool
+	literal.Parameters, literal.ReturnType, ok = parser.parseFunctionSignature(signatureContext)
+	if !ok {
+		return &InvalidExpression{literal.FuncToken}
+	}
+
+	var functionContext *types.Context
+	functionContext, literal.FunctionType = parser.newFunctionContext(signatureContext, nil, literal.TypeParameters,
+		literal.Parameters, literal.ReturnType)
+
+	literal.FunctionContext = types.CloneContext(functionContext)
+	literal.Body = parser.parseFunctionBody(functionContext, literal.FunctionContext)
+	if literal.Body == nil {
+		return &InvalidExpression{literal.FuncToken}
+	}
+
+	return literal
+}
+
+func (parser *Parser) parseStructLiteral(context *types.Context, typeName *Identifier, structType *types.Struct) Expression {
+	parser.consume()
+	structLiteral := &StructLiteral{
+		TypeName:   typeName,
+		Type:       structType,
+		FieldNames: make([]*Identifier, 0),
+		Values:     make([]Expression, 0),
+	}
+
+	for parser.peek().Type != token.RBrace {
+		if !parser.assertNext(token.Ident) {
+			return &InvalidExpression{parser.current()}
+		}
+		fieldName := &Identifier{IdentToken: parser.current(), Value: parser.current().Literal}
+		if !parser.assertNext(token.Colon) {
+			return &InvalidExpression{parser.current()}
+		}
+		parser.consume()
+		value := parser.parseExpression(context, ExpressionLowest)
+		if isInvalid(value) {
+			return value
+		}
+		structLiteral.FieldNames = append(structLiteral.FieldNames, fieldName)
+		structLiteral.Values = append(structLiteral.Values, value)
+		if parser.peek().Type != token.Comma {
+			break
+		}
+		parser.consume()
+	}
+
+	if !parser.assertNext(token.RBrace) {
+		return &InvalidExpression{typeName.IdentToken}
+	}
+
+	return structLiteral
+}
+
+func (parser *Parser) parseEnumLiteral(context *types.Context, typeName *Identifier, enumType *types.Enum) Expression {
+	parser.consume()
+	if !parser.assertNext(token.Ident) {
+		return &InvalidExpression{parser.current()}
+	}
+	variant := &Identifier{IdentToken: parser.current(), Value: parser.current().Literal}
+	if _, exists := enumType.Variants[variant.Value]; !exists {
+		parser.error(variant.IdentToken, "Variant '%s' does not exist on '%s'", variant.Value, enumType.ToString())
+		return &InvalidExpression{variant.IdentToken}
+	}
+
+	enumLiteral := &EnumLiteral{TypeName: typeName, Type: enumType, Variant: variant, Arguments: make([]Expression, 0)}
+	if parser.peek().Type == token.LParen {
+		parser.consume()
+		parser.consume()
+		var names []*Identifier
+		enumLiteral.Arguments, names = parser.parseArgumentList(context)
+		if enumLiteral.Arguments == nil {
+			return &InvalidExpression{variant.IdentToken}
+		}
+		for _, name := range names {
+			if name != nil {
+				parser.error(name.IdentToken, "Named arguments are not supported for enum variants")
+				return &InvalidExpression{name.IdentToken}
+			}
+		}
+		for _, argument := range enumLiteral.Arguments {
+			if isInvalid(argument) {
+				return argument
+			}
+		}
+	}
+
+	return enumLiteral
+}
+
+func (parser *Parser) parseMapLiteral(context *types.Context) Expression {
+	lBraceToken := parser.current()
+	keys, values := make([]Expression, 0), make([]Expression, 0)
+
+	for parser.peek().Type != token.RBrace {
+		parser.consume()
+		key := parser.parseExpression(context, ExpressionLowest)
+		if isInvalid(key) {
+			return key
+		}
+		if !parser.assertNext(token.Colon) {
+			return &InvalidExpression{parser.current()}
+		}
+		parser.consume()
+		value := parser.parseExpression(context, ExpressionLowest)
+		if isInvalid(value) {
+			return value
+		}
+		keys, values = append(keys, key), append(values, value)
+		if parser.peek().Type != token.Comma {
+			break
+		}
+		parser.consume()
+	}
+
+	if !parser.assertNext(token.RBrace) {
+		return &InvalidExpression{lBraceToken}
+	}
+
+	return &MapLiteral{
+		LBraceToken: lBraceToken,
+		Keys:        keys,
+		Values:      values,
+	}
+}
+
 /** infix expressions **/
 
 func (parser *Parser) parseInfixExpression(context *types.Context, left Expression) Expression {
 	currentToken := parser.consume()
 	precedence := expressionPrecedences[currentToken.Type]
+	if currentToken.Type == token.Power {
+		precedence-- // right associative
+	}
 
-	right := parser.parseExpression(context, precedence)
-
-	return &InfixExpression{
+	infixExpression := &InfixExpression{
 		OperatorToken: currentToken,
 		Left:          left,
 		Operator:      currentToken.Type,
-		Right:         right,
+	}
+	infixExpression.Right = parser.parseExpression(getRightContext(infixExpression, context), precedence)
+
+	return infixExpression
+}
+
+func (parser *Parser) parseIsExpression(context *types.Context, left Expression) Expression {
+	isToken := parser.consume()
+	return &IsExpression{
+		IsToken:    isToken,
+		Expression: left,
+		Type:       parser.parseType(context, TypeLowest),
+		Context:    context,
+	}
+}
+
+func (parser *Parser) parseConditionalExpression(context *types.Context, condition Expression) Expression {
+	qmarkToken := parser.consume()
+	consequence := parser.parseExpression(extendNarrowedContext(condition, context, true), ExpressionLowest)
+	if isInvalid(consequence) {
+		return consequence
+	}
+	if !parser.assertNext(token.Colon) {
+		return &InvalidExpression{qmarkToken}
+	}
+	colonToken := parser.current()
+
+	parser.consume()
+	alternative := parser.parseExpression(extendNarrowedContext(condition, context, false), ExpressionConditional-1) // right associative
+	if isInvalid(alternative) {
+		return alternative
+	}
+
+	return &ConditionalExpression{
+		QmarkToken:  qmarkToken,
+		ColonToken:  colonToken,
+		Condition:   condition,
+		Consequence: consequence,
+		Alternative: alternative,
+	}
+}
+
+func (parser *Parser) parseMatchExpression(context *types.Context) Expression {
+	matchToken := parser.consume()
+	subject := parser.parseExpression(context, ExpressionLowest)
+	if isInvalid(subject) {
+		return subject
+	}
+	if !parser.assertNext(token.LBrace) {
+		return &InvalidExpression{matchToken}
+	}
+
+	matchExpression := &MatchExpression{MatchToken: matchToken, Subject: subject, Arms: make([]*MatchArm, 0)}
+	for parser.peek().Type != token.RBrace {
+		parser.consume()
+		arm := parser.parseMatchArm(context, subject)
+		if arm == nil {
+			return &InvalidExpression{matchToken}
+		}
+		matchExpression.Arms = append(matchExpression.Arms, arm)
+
+		if parser.peek().Type == token.Comma {
+			parser.consume()
+		} else if _, isBlock := arm.Body.(*BlockStatement); !isBlock {
+			break
+		}
+	}
+	if !parser.assertNext(token.RBrace) {
+		return &InvalidExpression{matchToken}
+	}
+
+	return matchExpression
+}
+
+func (parser *Parser) parseMatchArm(context *types.Context, subject Expression) *MatchArm {
+	arm := &MatchArm{}
+
+	switch currentToken := parser.current(); {
+	case currentToken.Type == token.Ident && currentToken.Literal == "_":
+		arm.Wildcard = true
+	case currentToken.Type == token.Is:
+		parser.consume()
+		arm.TypePattern = parser.parseType(context, TypeLowest)
+	default:
+		for {
+			pattern := parser.parseExpression(context, ExpressionBitwiseOr)
+			if isInvalid(pattern) {
+				return nil
+			}
+			if enumLiteral, isEnum := pattern.(*EnumLiteral); isEnum {
+				pattern = parser.parseEnumPattern(enumLiteral)
+			}
+			arm.Patterns = append(arm.Patterns, pattern)
+			if parser.peek().Type != token.Pipe {
+				break
+			}
+			parser.consume()
+			parser.consume()
+		}
+	}
+
+	if !parser.assertNext(token.Arrow) {
+		return nil
+	}
+	arm.ArrowToken = parser.current()
+	parser.consume()
+
+	arm.Context = types.ExtendContext(context)
+	parser.defineEnumPatternBindings(arm)
+	if identifier, isIdentifier := subject.(*Identifier); isIdentifier && arm.TypePattern != nil {
+		// the subject has the tested type inside the arm
+		if subjectType, ok := context.GetMemberType(identifier.Value); ok {
+			narrowContext(arm.Context, map[string]types.Type{
+				identifier.Value: types.Narrow(subjectType, arm.TypePattern, context),
+			})
+		}
+	}
+
+	if parser.current().Type == token.LBrace {
+		arm.Body = parser.parseBlockStatement(arm.Context)
+	} else {
+		firstToken := parser.current()
+		expression := parser.parseExpression(arm.Context, ExpressionLowest)
+		if isInvalid(expression) {
+			return nil
+		}
+		arm.Body = &ExpressionStatement{FirstToken: firstToken, Expression: expression}
+	}
+
+	return arm
+}
+
+func (parser *Parser) parseEnumPattern(enumLiteral *EnumLiteral) *EnumPattern {
+	enumPattern := &EnumPattern{
+		TypeName: enumLiteral.TypeName,
+		Type:     enumLiteral.Type,
+		Variant:  enumLiteral.Variant,
+		Bindings: make([]*Identifier, 0),
+	}
+
+	payloadTypes := enumLiteral.Type.Variants[enumLiteral.Variant.Value]
+	if len(enumLiteral.Arguments) != len(payloadTypes) {
+		parser.error(enumLiteral.Variant.IdentToken, "Mismatching amount of values (%d vs %d)",
+			len(enumLiteral.Arguments), len(payloadTypes))
+	}
+	for _, argument := range enumLiteral.Arguments {
+		binding, isIdentifier := argument.(*Identifier)
+		if !isIdentifier {
+			parser.error(argument.Token(), "Invalid pattern binding")
+			continue
+		}
+		enumPattern.Bindings = append(enumPattern.Bindings, binding)
+	}
+	return enumPattern
+}
+
+func (parser *Parser) defineEnumPatternBindings(arm *MatchArm) {
+	for _, pattern := range arm.Patterns {
+		enumPattern, isEnumPattern := pattern.(*EnumPattern)
+		if !isEnumPattern || len(enumPattern.Bindings) == 0 {
+			continue
+		}
+		if len(arm.Patterns) > 1 {
+			parser.error(enumPattern.Variant.IdentToken, "Cannot bind values in alternative patterns")
+			return
+		}
+		payloadTypes := enumPattern.Type.Variants[enumPattern.Variant.Value]
+		for i, binding := range enumPattern.Bindings {
+			if binding.Value == "_" || i >= len(payloadTypes) {
+				continue
+			}
+			if _, ok := arm.Context.DefineMemberType(binding.Value, payloadTypes[i]); !ok {
+				parser.error(binding.IdentToken, "Cannot redefine '%s'", binding.Value)
+			}
+		}
 	}
 }
 
@@ -206,24 +684,42 @@ func (parser *Parser) parseAssignmentExpression(context *types.Context, left Exp
 	assignToken := parser.consume()
 	right := parser.parseExpression(context, ExpressionAssignment)
 
-	ident, isIdent := left.(*Identifier)
-	if !isIdent {
+	switch left := left.(type) {
+	case *Identifier:
+		return &AssignmentExpression{
+			IdentToken:  left.IdentToken,
+			AssignToken: assignToken,
+			Name:        left,
+			Expression:  right,
+		}
+	case *IndexExpression:
+		return &IndexAssignmentExpression{
+			AssignToken: assignToken,
+			Index:       left,
+			Expression:  right,
+		}
+	case *MemberAccessExpression:
+		if left.Optional {
+			parser.error(left.Token(), "Invalid assignment target")
+			return &InvalidExpression{InvalidToken: assignToken}
+		}
+		return &MemberAssignmentExpression{
+			AssignToken: assignToken,
+			Member:      left,
+			Expression:  right,
+		}
+	case *InvalidExpression:
+		return left
+	default:
 		erroneousToken := left.Token()
-		parser.error(erroneousToken, "Invalid identifier")
+		parser.error(erroneousToken, "Invalid assignment target")
 		return &InvalidExpression{InvalidToken: assignToken}
 	}
-
-	return &AssignmentExpression{
-		IdentToken:  ident.IdentToken,
-		AssignToken: assignToken,
-		Name:        ident,
-		Expression:  right,
-	}
 }
 
 func (parser *Parser) parseCallExpression(context *types.Context, function Expression) Expression {
 	currentToken := parser.consume()
-	argumentList := parser.parseArgumentList(context)
+	argumentList, argumentNames := parser.parseArgumentList(context)
 
 	if argumentList == nil {
 		return &InvalidExpression{currentToken}
@@ -236,9 +732,10 @@ func (parser *Parser) parseCallExpression(context *types.Context, function Expre
 	}
 
 	return &CallExpression{
-		ParenToken: currentToken,
-		Function:   function,
-		Arguments:  argumentList,
+		ParenToken:    currentToken,
+		Function:      function,
+		Arguments:     argumentList,
+		ArgumentNames: argumentNames,
 	}
 }
 
@@ -250,6 +747,15 @@ func (parser *Parser) parseIncrementInfixExpression(_ *types.Context, identExpre
 func (parser *Parser) parseMemberAccessExpression(context *types.Context, left Expression) Expression {
 	dotToken := parser.consume()
 	leftType := parser.getExpressionType(left, context)
+	optional := dotToken.Type == token.QmarkDot
+	if optional && !isNever(leftType) {
+		optionalType, isOptional := leftType.(*types.Optional)
+		if !isOptional {
+			parser.error(dotToken, "Type '%s' is not optional", leftType.ToString())
+			return &InvalidExpression{InvalidToken: dotToken}
+		}
+		leftType = optionalType.Base
+	}
 	right := parser.parseExpression(types.GetMemberTypeContext(context, leftType), ExpressionPostfix)
 
 	ident, isIdent := right.(*Identifier)
@@ -272,6 +778,25 @@ func (parser *Parser) parseMemberAccessExpression(context *types.Context, left E
 		Member:     ident,
 		ParentType: resolvedParentType,
 		MemberType: memberType,
+		Optional:   optional,
+	}
+}
+
+func (parser *Parser) parseIndexExpression(context *types.Context, left Expression) Expression {
+	lBracketToken := parser.consume()
+	index := parser.parseExpression(context, ExpressionLowest)
+
+	if isInvalid(index) {
+		return index
+	}
+	if !parser.assertNext(token.RBracket) {
+		return &InvalidExpression{lBracketToken}
+	}
+
+	return &IndexExpression{
+		LBracketToken: lBracketToken,
+		Expression:    left,
+		Index:         index,
 	}
 }
 
@@ -294,15 +819,26 @@ func (parser *Parser) parseIncrementExpression(operatorToken *token.Token, ident
 	}
 }
 
-func (parser *Parser) parseArgumentList(context *types.Context) []Expression {
+func (parser *Parser) parseArgumentList(context *types.Context) ([]Expression, []*Identifier) {
 
 	arguments := make([]Expression, 0)
+	var names []*Identifier
 
 	if parser.current().Type == token.RParen {
-		return arguments
+		return arguments, names
 	}
 
 	for {
+		if parser.current().Type == token.Ident && parser.peek().Type == token.Colon {
+			if names == nil {
+				names = make([]*Identifier, len(arguments))
+			}
+			nameToken := parser.consume()
+			names = append(names, &Identifier{IdentToken: nameToken, Value: nameToken.Literal})
+			parser.consume()
+		} else if names != nil {
+			names = append(names, nil)
+		}
 		arguments = append(arguments, parser.parseExpression(context, ExpressionLowest))
 		if parser.peek().Type == token.Comma {
 			parser.consume()
@@ -313,9 +849,28 @@ func (parser *Parser) parseArgumentList(context *types.Context) []Expression {
 	}
 
 	if !parser.assertNext(token.RParen) {
+		return nil, nil
+	}
+	return arguments, names
+}
+
+func (parser *Parser) parseExpressionList(context *types.Context, closingType token.Type) []Expression {
+
+	expressions := make([]Expression, 0)
+
+	for parser.peek().Type != closingType {
+		parser.consume()
+		expressions = append(expressions, parser.parseExpression(context, ExpressionLowest))
+		if parser.peek().Type != token.Comma {
+			break
+		}
+		parser.consume()
+	}
+
+	if !parser.assertNext(closingType) {
 		return nil
 	}
-	return arguments
+	return expressions
 }
 
 func isInvalid(expression Expression) bool {
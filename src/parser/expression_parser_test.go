@@ -33,6 +33,86 @@ func TestExpressionParser(t *testing.T) {
 		},
 	)
 
+	assertExpression(t,
+		"a | b & c << 1",
+		&InfixExpression{
+			Left:     &Identifier{Value: "a"},
+			Operator: token.Pipe,
+			Right: &InfixExpression{
+				Left:     &Identifier{Value: "b"},
+				Operator: token.Amp,
+				Right: &InfixExpression{
+					Left:     &Identifier{Value: "c"},
+					Operator: token.ShiftLeft,
+					Right:    &IntegerLiteral{Value: 1},
+				},
+			},
+		},
+	)
+
+	assertExpression(t,
+		"-2 ** 3 ** 2",
+		&PrefixExpression{
+			Operator: token.Minus,
+			Expression: &InfixExpression{
+				Left:     &IntegerLiteral{Value: 2},
+				Operator: token.Power,
+				Right: &InfixExpression{
+					Left:     &IntegerLiteral{Value: 3},
+					Operator: token.Power,
+					Right:    &IntegerLiteral{Value: 2},
+				},
+			},
+		},
+	)
+
+	assertExpression(t,
+		"a || b ? 1 : c ? 2 : 3",
+		&ConditionalExpression{
+			Condition: &InfixExpression{
+				Left:     &Identifier{Value: "a"},
+				Operator: token.LogicalOr,
+				Right:    &Identifier{Value: "b"},
+			},
+			Consequence: &IntegerLiteral{Value: 1},
+			Alternative: &ConditionalExpression{
+				Condition:   &Identifier{Value: "c"},
+				Consequence: &IntegerLiteral{Value: 2},
+				Alternative: &IntegerLiteral{Value: 3},
+			},
+		},
+	)
+
+	assertExpression(t,
+		"a ?? b || c ? 1 : 2",
+		&ConditionalExpression{
+			Condition: &InfixExpression{
+				Left:     &Identifier{Value: "a"},
+				Operator: token.Nullish,
+				Right: &InfixExpression{
+					Left:     &Identifier{Value: "b"},
+					Operator: token.LogicalOr,
+					Right:    &Identifier{Value: "c"},
+				},
+			},
+			Consequence: &IntegerLiteral{Value: 1},
+			Alternative: &IntegerLiteral{Value: 2},
+		},
+	)
+
+	assertExpression(t,
+		"(a, (b), (c, 1))",
+		&TupleLiteral{
+			Elements: []Expression{
+				&Identifier{Value: "a"},
+				&Identifier{Value: "b"},
+				&TupleLiteral{
+					Elements: []Expression{&Identifier{Value: "c"}, &IntegerLiteral{Value: 1}},
+				},
+			},
+		},
+	)
+
 	assertExpression(t,
 		"func(++x, !(a || b))",
 		&CallExpression{
@@ -63,6 +143,20 @@ func TestExpressionParser(t *testing.T) {
 		},
 	)
 
+	assertExpression(t,
+		"xs[1] = [2, 3]",
+		&IndexAssignmentExpression{
+			Index: &IndexExpression{
+				Expression: &Identifier{Value: "xs"},
+				Index:      &IntegerLiteral{Value: 1},
+			},
+			Expression: &ArrayLiteral{
+				Elements: []Expression{&IntegerLiteral{Value: 2}, &IntegerLiteral{Value: 3}},
+				Type:     &types.Array{ElementType: &types.Int{}},
+			},
+		},
+	)
+
 	assertExpression(t,
 		"+2",
 		&InvalidExpression{},
@@ -81,6 +175,7 @@ func assertExpression(t *testing.T, input string, expected Expression) {
 
 	context := types.NewContext()
 	expression := parser.parseExpression(context, ExpressionLowest)
+	parser.getExpressionType(expression, context)
 
 	ignoreTokens := cmp.Comparer(func(t1, t2 *token.Token) bool {
 		return true
@@ -12,6 +12,20 @@ type Parser struct {
 	errors   []*errors.ParserError
 	tokens   []*token.Token
 	position int
+	modules  *moduleStore
+
+	// uninferredTypes holds the types of empty literals whose element types were left to inference
+	uninferredTypes map[types.Type]bool
+
+	prefixExpressionParseFunctions map[token.Type]func(*types.Context) Expression
+	infixExpressionParseFunctions  map[token.Type]func(*types.Context, Expression) Expression
+	prefixTypeParseFunctions       map[token.Type]func(*types.Context) types.Type
+	infixTypeParseFunctions        map[token.Type]func(*types.Context, types.Type) types.Type
+}
+
+type moduleStore struct {
+	modules map[string]*Module
+	loading []string
 }
 
 func New(lexer *lexer.Lexer) *Parser {
@@ -24,7 +38,15 @@ func New(lexer *lexer.Lexer) *Parser {
 		}
 	}
 
-	parser := &Parser{tokens: tokens, errors: lexer.Errors}
+	parser := &Parser{
+		tokens:                         tokens,
+		errors:                         lexer.Errors,
+		uninferredTypes:                make(map[types.Type]bool),
+		prefixExpressionParseFunctions: make(map[token.Type]func(*types.Context) Expression),
+		infixExpressionParseFunctions:  make(map[token.Type]func(*types.Context, Expression) Expression),
+		prefixTypeParseFunctions:       make(map[token.Type]func(*types.Context) types.Type),
+		infixTypeParseFunctions:        make(map[token.Type]func(*types.Context, types.Type) types.Type),
+	}
 	parser.registerExpressionParseFunctions()
 	parser.registerTypeParseFunctions()
 	return parser
@@ -38,7 +60,7 @@ func (parser *Parser) current() *token.Token {
 	if parser.position < len(parser.tokens) {
 		return parser.tokens[parser.position]
 	} else {
-		return parser.tokens[parser.position-1]
+		return parser.tokens[len(parser.tokens)-1] // EOF
 	}
 }
 
@@ -56,6 +78,17 @@ func (parser *Parser) peek() *token.Token {
 	}
 }
 
+func (parser *Parser) assertNextGT() bool {
+	// '>>' closes two nested type argument lists
+	if nextToken := parser.peek(); nextToken.Type == token.ShiftRight {
+		first := token.New(token.GT, "", nextToken.Line, nextToken.Col, nextToken.File)
+		second := token.New(token.GT, "", nextToken.Line, nextToken.Col+1, nextToken.File)
+		rest := append([]*token.Token{first, second}, parser.tokens[parser.position+2:]...)
+		parser.tokens = append(parser.tokens[:parser.position+1], rest...)
+	}
+	return parser.assertNext(token.GT)
+}
+
 func (parser *Parser) assertNext(tokenType token.Type) bool {
 	if nextToken := parser.peek(); nextToken.Type == tokenType {
 		parser.consume()
@@ -71,7 +104,8 @@ func (parser *Parser) ParseProgram(context *types.Context) (*Program, []*errors.
 
 	program := &Program{}
 	program.Statements = []Statement{}
-	program.Context = types.ExtendContext(context)
+	importContext := types.ExtendContext(context)
+	program.Context = types.ExtendContext(importContext)
 
 	for parser.current().Type != token.EOF {
 		if parser.current().Type == token.Semi || parser.current().Type == token.Illegal {
@@ -79,7 +113,12 @@ func (parser *Parser) ParseProgram(context *types.Context) (*Program, []*errors.
 			continue
 		}
 
-		statement := parser.parseStatement(program.Context)
+		var statement Statement
+		if parser.current().Type == token.Import {
+			statement = parser.parseImportStatement(importContext)
+		} else {
+			statement = parser.parseStatement(program.Context)
+		}
 
 		if statement != nil && !reflect.ValueOf(statement).IsNil() {
 			program.Statements = append(program.Statements, statement)
@@ -87,48 +126,195 @@ func (parser *Parser) ParseProgram(context *types.Context) (*Program, []*errors.
 		parser.consume()
 	}
 
-	parser.doesReturn(context, program)
+	parser.doesReturn(program)
 	return program, parser.errors
 }
 
-func (parser *Parser) doesReturn(context *types.Context, statement Statement) bool {
+func (parser *Parser) doesReturn(statement Statement) bool {
 
 	switch statement := statement.(type) {
 	case *Program:
 		for _, statement := range statement.Statements {
-			parser.doesReturn(context, statement)
-		}
-	case *ReturnStatement:
-		if context.ReturnType != nil {
-			if !isNever(context.ReturnType) {
-				returnType := parser.getExpressionType(statement.Expression, context)
-				if !isNever(returnType) && !context.ReturnType.IsAssignable(returnType, context) {
-					parser.error(statement.ReturnToken, "Type '%s' is not assignable to '%s'", returnType.ToString(),
-						context.ReturnType.ToString())
-				}
-			}
-			return true
-		} else {
-			parser.error(statement.ReturnToken, "Illegal return statement")
+			parser.doesReturn(statement)
 		}
+	case *ReturnStatement, *ThrowStatement:
+		return true
 	case *BlockStatement:
-		newContext := statement.Context
 		returned := false
 		for _, statement := range statement.Statements {
 			if returned {
 				parser.error(statement.Token(), "Unreachable code")
 				return true
 			}
-			returned = parser.doesReturn(newContext, statement)
+			returned = parser.doesReturn(statement)
+		}
+		return returned
+	case *IfStatement:
+		return parser.doesReturn(statement.Statement) && parser.doesReturn(statement.Alternative)
+	case *TryStatement:
+		if statement == nil {
+			return false
+		}
+		bodyReturned := parser.doesReturn(statement.Body)
+		catchReturned := statement.Catch == nil || parser.doesReturn(statement.Catch)
+		finallyReturned := statement.Finally != nil && parser.doesReturn(statement.Finally)
+		return bodyReturned && catchReturned || finallyReturned
+	case *MatchStatement:
+		returned := statement.Match.Exhaustive
+		for _, arm := range statement.Match.Arms {
+			if !parser.doesReturn(arm.Body) {
+				returned = false
+			}
 		}
 		return returned
+	}
+	return false
+}
+
+func doesExit(statement Statement) bool {
+
+	switch statement := statement.(type) {
+	case *ReturnStatement, *BreakStatement, *ContinueStatement, *ThrowStatement:
+		return true
+	case *BlockStatement:
+		for _, statement := range statement.Statements {
+			if doesExit(statement) {
+				return true
+			}
+		}
 	case *IfStatement:
-		return parser.doesReturn(statement.StatementContext, statement.Statement) &&
-			parser.doesReturn(statement.AlternativeContext, statement.Alternative)
+		return doesExit(statement.Statement) && doesExit(statement.Alternative)
+	case *TryStatement:
+		if statement == nil {
+			return false
+		}
+		if statement.Finally != nil && doesExit(statement.Finally) {
+			return true
+		}
+		return doesExit(statement.Body) && (statement.Catch == nil || doesExit(statement.Catch))
+	case *MatchStatement:
+		if statement == nil || !statement.Match.Exhaustive {
+			return false
+		}
+		for _, arm := range statement.Match.Arms {
+			if !doesExit(arm.Body) {
+				return false
+			}
+		}
+		return true
 	}
 	return false
 }
 
+func (parser *Parser) parseFunctionSignature(context *types.Context) ([]*Parameter, types.Type, bool) {
+
+	parameters := parser.parseParameterList(context)
+	if parameters == nil {
+		return nil, nil, false
+	}
+	parser.consume()
+
+	if parser.current().Type == token.LBrace {
+		return parameters, &types.Void{}, true
+	}
+
+	returnType := parser.parseType(context, TypeLowest)
+	if !parser.assertNext(token.LBrace) {
+		return nil, nil, false
+	}
+	return parameters, returnType, true
+}
+
+func (parser *Parser) parseTypeParameterList(context *types.Context) ([]*types.TypeParameter, *types.Context) {
+
+	typeParameterContext := types.ExtendContext(context)
+	typeParameters := make([]*types.TypeParameter, 0)
+	for {
+		if !parser.assertNext(token.Ident) {
+			return nil, nil
+		}
+		identToken := parser.current()
+		typeParameter := &types.TypeParameter{Name: identToken.Literal}
+
+		if parser.peek().Type == token.Colon {
+			parser.consume()
+			parser.consume()
+			typeParameter.Constraint = parser.parseType(typeParameterContext, TypeLowest)
+		}
+
+		if _, ok := typeParameterContext.DefineType(typeParameter.Name, typeParameter); !ok {
+			parser.error(identToken, "Cannot redefine type '%s'", typeParameter.Name)
+		}
+		typeParameters = append(typeParameters, typeParameter)
+
+		if parser.peek().Type == token.Comma {
+			parser.consume()
+		} else {
+			break
+		}
+	}
+
+	if !parser.assertNextGT() {
+		return nil, nil
+	}
+	return typeParameters, typeParameterContext
+}
+
+func (parser *Parser) newFunctionContext(context *types.Context, thisType types.Type, typeParameters []*types.TypeParameter, parameters []*Parameter, returnType types.Type) (*types.Context, *types.Function) {
+
+	parameterTypes := make([]types.Type, 0)
+	parameterNames := make([]string, 0)
+	defaultCount := 0
+	functionContext := types.ExtendFunctionContext(context, returnType)
+	if thisType != nil {
+		functionContext.DefineMemberType("this", thisType)
+	}
+	for _, parameter := range parameters {
+		parameterTypes = append(parameterTypes, parameter.Type)
+		parameterNames = append(parameterNames, parameter.Name.Value)
+		if parameter.Default != nil {
+			defaultCount++
+		}
+		parameterType := parameter.Type
+		if parameter.Variadic {
+			parameterType = &types.Array{ElementType: parameterType}
+		}
+		_, ok := functionContext.DefineMemberType(parameter.Name.Value, parameterType)
+		if !ok {
+			parser.error(parameter.Token, "Cannot redefine '%s'", parameter.Name.Value)
+		}
+	}
+
+	return functionContext, &types.Function{
+		TypeParameters: typeParameters,
+		ParameterTypes: parameterTypes,
+		ParameterNames: parameterNames,
+		DefaultCount:   defaultCount,
+		Variadic:       len(parameters) > 0 && parameters[len(parameters)-1].Variadic,
+		ReturnType:     returnType,
+	}
+}
+
+func (parser *Parser) parseFunctionBody(functionContext *types.Context, bodyContext *types.Context) *BlockStatement {
+
+	body := parser.parseBlockStatement(bodyContext)
+	if body == nil {
+		return nil
+	}
+
+	if _, isVoid := functionContext.ReturnType.(*types.Void); !isVoid {
+		if returns := parser.doesReturn(body); !returns {
+			erroneousToken := body.RBraceToken
+			if erroneousToken == nil {
+				erroneousToken = body.LBraceToken
+			}
+			parser.error(erroneousToken, "Missing return statement")
+		}
+	}
+
+	return body
+}
+
 func (parser *Parser) parseParameterList(context *types.Context) []*Parameter {
 
 	parameters := make([]*Parameter, 0)
@@ -142,6 +328,14 @@ func (parser *Parser) parseParameterList(context *types.Context) []*Parameter {
 		if parameter == nil {
 			return nil
 		}
+		if len(parameters) > 0 && parameters[len(parameters)-1].Variadic {
+			parser.error(parameters[len(parameters)-1].Token, "Variadic parameter '%s' must be the last parameter",
+				parameters[len(parameters)-1].Name.Value)
+		}
+		if parameter.Default == nil && !parameter.Variadic && len(parameters) > 0 && parameters[len(parameters)-1].Default != nil {
+			parser.error(parameter.Token, "Parameter '%s' without default value follows parameter with default value",
+				parameter.Name.Value)
+		}
 		parameters = append(parameters, parameter)
 		if parser.peek().Type == token.Comma {
 			parser.consume()
@@ -169,7 +363,29 @@ func (parser *Parser) parseParameter(context *types.Context) *Parameter {
 	}
 
 	parser.consume()
+	variadic := parser.current().Type == token.Ellipsis
+	if variadic {
+		parser.consume()
+	}
 	theType := parser.parseType(context, TypeLowest)
+	parameter := &Parameter{Token: identToken, Name: ident, Type: theType, Variadic: variadic}
+
+	if parser.peek().Type == token.Assign {
+		parser.consume()
+		if variadic {
+			parser.error(parser.current(), "Variadic parameter '%s' cannot have a default value", ident.Value)
+		}
+		parser.consume()
+		parameter.Default = parser.parseExpression(context, ExpressionLowest)
+		if isInvalid(parameter.Default) {
+			return nil
+		}
+		defaultType := parser.getExpressionType(parameter.Default, context)
+		if !isNever(defaultType) && !isNever(theType) && !theType.IsAssignable(defaultType, context) {
+			parser.error(parameter.Default.Token(), "Type '%s' is not assignable to '%s'", defaultType.ToString(),
+				theType.ToString())
+		}
+	}
 
-	return &Parameter{Token: identToken, Name: ident, Type: theType}
+	return parameter
 }
@@ -1,14 +1,21 @@
 package parser
 
 import (
+	"bananascript/src/lexer"
 	"bananascript/src/token"
 	"bananascript/src/types"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 func (parser *Parser) parseStatement(context *types.Context) Statement {
 	switch parser.current().Type {
-	case token.Let:
+	case token.Let, token.Const:
+		if parser.peek().Type == token.LParen {
+			return parser.parseDestructuringStatement(context)
+		}
 		return parser.parseLetStatement(context)
 	case token.Return:
 		return parser.parseReturnStatement(context)
@@ -18,15 +25,56 @@ func (parser *Parser) parseStatement(context *types.Context) Statement {
 		return parser.parseBlockStatement(context)
 	case token.If:
 		return parser.parseIfStatement(context)
+	case token.Match:
+		return parser.parseMatchStatement(context)
 	case token.While:
-		return parser.parseWhileStatement(context)
+		return parser.parseWhileStatement(context, nil)
+	case token.For:
+		return parser.parseForStatement(context, nil)
+	case token.Break:
+		return parser.parseBreakStatement(context)
+	case token.Continue:
+		return parser.parseContinueStatement(context)
+	case token.Throw:
+		return parser.parseThrowStatement(context)
+	case token.Try:
+		return parser.parseTryStatement(context)
+	case token.Import:
+		parser.error(parser.current(), "Imports are only allowed at the top level")
+		return parser.parseImportStatement(context)
 	case token.TypeDef:
 		return parser.parseTypeDefinitionStatement(context)
+	case token.Ident:
+		if parser.peek().Type == token.Colon {
+			return parser.parseLabeledStatement(context)
+		}
+		return parser.parseExpressionStatement(context)
 	default:
 		return parser.parseExpressionStatement(context)
 	}
 }
 
+func (parser *Parser) parseLabeledStatement(context *types.Context) Statement {
+	labelToken := parser.current()
+	label := &Identifier{IdentToken: labelToken, Value: labelToken.Literal}
+	parser.consume() // :
+	parser.consume()
+
+	if context.IsInLoop(label.Value) {
+		parser.error(labelToken, "Label '%s' is already defined", label.Value)
+	}
+
+	switch parser.current().Type {
+	case token.While:
+		return parser.parseWhileStatement(context, label)
+	case token.For:
+		return parser.parseForStatement(context, label)
+	default:
+		parser.error(labelToken, "Labels can only be used on loops")
+		return parser.parseStatement(context)
+	}
+}
+
 func (parser *Parser) parseExpressionStatement(context *types.Context) *ExpressionStatement {
 	statement := &ExpressionStatement{}
 	statement.Expression = parser.parseExpression(context, ExpressionLowest)
@@ -44,17 +92,27 @@ func (parser *Parser) parseReturnStatement(context *types.Context) *ReturnStatem
 
 	if parser.current().Type == token.Semi {
 		statement.Expression = &VoidLiteral{}
-		return statement
+	} else {
+		statement.Expression = parser.parseExpression(context, ExpressionLowest)
+		parser.assertNext(token.Semi)
 	}
 
-	statement.Expression = parser.parseExpression(context, ExpressionLowest)
-	parser.assertNext(token.Semi)
+	if context.ReturnType == nil {
+		parser.error(statement.ReturnToken, "Illegal return statement")
+	} else if !isNever(context.ReturnType) {
+		returnType := parser.getExpectedExpressionType(statement.Expression, context.ReturnType, context)
+		if !isNever(returnType) && !context.ReturnType.IsAssignable(returnType, context) {
+			parser.error(statement.ReturnToken, "Type '%s' is not assignable to '%s'", returnType.ToString(),
+				context.ReturnType.ToString())
+		}
+	}
 	return statement
 }
 
 func (parser *Parser) parseBlockStatement(context *types.Context) *BlockStatement {
 
-	newContext := types.ExtendContext(context)
+	blockContext := types.ExtendContext(context)
+	newContext := blockContext
 	openingBrace := parser.consume()
 	statements := make([]Statement, 0)
 
@@ -67,6 +125,9 @@ func (parser *Parser) parseBlockStatement(context *types.Context) *BlockStatemen
 		statement := parser.parseStatement(newContext)
 		if statement != nil && !reflect.ValueOf(statement).IsNil() {
 			statements = append(statements, statement)
+			if ifStatement, isIf := statement.(*IfStatement); isIf && ifStatement.Alternative == nil && doesExit(ifStatement.Statement) {
+				newContext = extendNarrowedContext(ifStatement.Condition, newContext, false)
+			}
 		}
 		parser.consume()
 	}
@@ -77,11 +138,11 @@ func (parser *Parser) parseBlockStatement(context *types.Context) *BlockStatemen
 		rBraceToken = nil
 	}
 
-	return &BlockStatement{Statements: statements, LBraceToken: openingBrace, RBraceToken: rBraceToken, Context: newContext}
+	return &BlockStatement{Statements: statements, LBraceToken: openingBrace, RBraceToken: rBraceToken, Context: blockContext}
 }
 
 func (parser *Parser) parseLetStatement(context *types.Context) *LetStatement {
-	statement := &LetStatement{LetToken: parser.current()}
+	statement := &LetStatement{LetToken: parser.current(), Constant: parser.current().Type == token.Const}
 	if !parser.assertNext(token.Ident) {
 		return nil
 	}
@@ -105,14 +166,27 @@ func (parser *Parser) parseLetStatement(context *types.Context) *LetStatement {
 		parser.consume()
 		statement.Value = parser.parseExpression(context, ExpressionLowest)
 	} else {
+		if statement.Constant {
+			parser.error(identToken, "Missing value for constant '%s'", name)
+		}
 		statement.Value = &NullLiteral{}
 	}
 
 	parser.assertNext(token.Semi)
 
-	inferredType := parser.getExpressionType(statement.Value, context)
+	inferredType := parser.getExpectedExpressionType(statement.Value, statement.Type, context)
 	if statement.Type == nil {
 		statement.Type = inferredType
+		if parser.isUninferred(inferredType) {
+			switch inferredType.(type) {
+			case *types.Array:
+				parser.error(statement.Value.Token(), "Cannot infer element type of empty array")
+			case *types.Map:
+				parser.error(statement.Value.Token(), "Cannot infer key and value types of empty map")
+			default:
+				parser.error(statement.Value.Token(), "Cannot infer element type of empty array or map")
+			}
+		}
 	} else if !statement.Type.IsAssignable(inferredType, context) {
 		erroneousToken := statement.Value.Token()
 		if erroneousToken == nil {
@@ -124,13 +198,98 @@ func (parser *Parser) parseLetStatement(context *types.Context) *LetStatement {
 		}
 	}
 
-	_, ok := context.DefineMemberType(name, statement.Type)
+	var ok bool
+	if statement.Constant {
+		_, ok = context.DefineConstantType(name, statement.Type)
+	} else {
+		_, ok = context.DefineMemberType(name, statement.Type)
+	}
 	if !ok {
 		parser.error(identToken, "Cannot redefine '%s'", name)
 	}
 	return statement
 }
 
+func (parser *Parser) parseDestructuringStatement(context *types.Context) *DestructuringStatement {
+	statement := &DestructuringStatement{LetToken: parser.current(), Constant: parser.current().Type == token.Const}
+	parser.consume()
+
+	for {
+		if !parser.assertNext(token.Ident) {
+			return nil
+		}
+		statement.Names = append(statement.Names, &Identifier{IdentToken: parser.current(), Value: parser.current().Literal})
+		if parser.peek().Type != token.Comma {
+			break
+		}
+		parser.consume()
+	}
+	if !parser.assertNext(token.RParen) {
+		return nil
+	}
+
+	assignmentToken := token.Define
+	if parser.peek().Type == token.Colon {
+		parser.consume()
+		parser.consume()
+		statement.Type = parser.parseType(context, TypeLowest)
+		assignmentToken = token.Assign
+	}
+	if !parser.assertNext(assignmentToken) {
+		return nil
+	}
+	parser.consume()
+	statement.Value = parser.parseExpression(context, ExpressionLowest)
+	parser.assertNext(token.Semi)
+
+	inferredType := parser.getExpectedExpressionType(statement.Value, statement.Type, context)
+	if statement.Type == nil {
+		statement.Type = inferredType
+	} else if !isNever(statement.Type) && !isNever(inferredType) && !statement.Type.IsAssignable(inferredType, context) {
+		parser.error(statement.Value.Token(), "Type '%s' is not assignable to '%s'", inferredType.ToString(),
+			statement.Type.ToString())
+	}
+
+	elementTypes := make([]types.Type, len(statement.Names))
+	switch tupleType := statement.Type.(type) {
+	case *types.Never:
+		for i := range elementTypes {
+			elementTypes[i] = tupleType
+		}
+	case *types.Tuple:
+		if len(tupleType.ElementTypes) != len(statement.Names) {
+			parser.error(statement.LetToken, "Mismatching amount of values (%d vs %d)", len(statement.Names),
+				len(tupleType.ElementTypes))
+			for i := range elementTypes {
+				elementTypes[i] = &types.Never{}
+			}
+		} else {
+			copy(elementTypes, tupleType.ElementTypes)
+		}
+	default:
+		parser.error(statement.LetToken, "Cannot destructure '%s'", statement.Type.ToString())
+		for i := range elementTypes {
+			elementTypes[i] = &types.Never{}
+		}
+	}
+
+	for i, name := range statement.Names {
+		if name.Value == "_" {
+			continue
+		}
+		var ok bool
+		if statement.Constant {
+			_, ok = context.DefineConstantType(name.Value, elementTypes[i])
+		} else {
+			_, ok = context.DefineMemberType(name.Value, elementTypes[i])
+		}
+		if !ok {
+			parser.error(name.IdentToken, "Cannot redefine '%s'", name.Value)
+		}
+	}
+	return statement
+}
+
 func (parser *Parser) parseFunctionDefinitionStatement(context *types.Context) *FunctionDefinitionStatement {
 
 	statement := &FunctionDefinitionStatement{FuncToken: parser.current()}
@@ -151,45 +310,29 @@ func (parser *Parser) parseFunctionDefinitionStatement(context *types.Context) *
 	name := identToken.Literal
 	statement.Name = &Identifier{IdentToken: identToken, Value: name}
 
-	if !parser.assertNext(token.LParen) {
-		return nil
-	}
-
-	statement.Parameters = parser.parseParameterList(context)
-	if statement.Parameters == nil {
-		return nil
-	}
-	parser.consume()
-
-	if parser.current().Type == token.LBrace {
-		statement.ReturnType = &types.Void{}
-	} else {
-		statement.ReturnType = parser.parseType(context, TypeLowest)
-		if !parser.assertNext(token.LBrace) {
+	signatureContext := context
+	if parser.peek().Type == token.LT {
+		parser.consume()
+		statement.TypeParameters, signatureContext = parser.parseTypeParameterList(context)
+		if statement.TypeParameters == nil {
 			return nil
 		}
 	}
 
-	parameterTypes := make([]types.Type, 0)
-	functionContext := types.ExtendContext(context)
-	functionContext.ReturnType = statement.ReturnType
-	if statement.ThisType != nil {
-		functionContext.DefineMemberType("this", statement.ThisType)
-	}
-	for _, parameter := range statement.Parameters {
-		parameterTypes = append(parameterTypes, parameter.Type)
-		_, ok := functionContext.DefineMemberType(parameter.Name.Value, parameter.Type)
-		if !ok {
-			parser.error(parameter.Token, "Cannot redefine '%s'", parameter.Name.Value)
-		}
+	if !parser.assertNext(token.LParen) {
+		return nil
 	}
 
-	statement.FunctionType = &types.Function{
-		ParameterTypes: parameterTypes,
-		ReturnType:     statement.ReturnType,
+	var ok bool
+	statement.Parameters, statement.ReturnType, ok = parser.parseFunctionSignature(signatureContext)
+	if !ok {
+		return nil
 	}
 
-	var ok bool
+	var functionContext *types.Context
+	functionContext, statement.FunctionType = parser.newFunctionContext(signatureContext, statement.ThisType,
+		statement.TypeParameters, statement.Parameters, statement.ReturnType)
+
 	if statement.ThisType != nil {
 		_, ok = context.DefineTypeMemberType(name, statement.FunctionType, statement.ThisType)
 	} else {
@@ -201,21 +344,11 @@ func (parser *Parser) parseFunctionDefinitionStatement(context *types.Context) *
 	}
 
 	statement.FunctionContext = types.CloneContext(functionContext)
-	statement.Body = parser.parseBlockStatement(statement.FunctionContext)
+	statement.Body = parser.parseFunctionBody(functionContext, statement.FunctionContext)
 	if statement.Body == nil {
 		return nil
 	}
 
-	if _, isVoid := statement.ReturnType.(*types.Void); !isVoid {
-		if returns := parser.doesReturn(types.CloneContext(functionContext), statement.Body); !returns {
-			erroneousToken := statement.Body.RBraceToken
-			if erroneousToken == nil {
-				erroneousToken = statement.Body.LBraceToken
-			}
-			parser.error(erroneousToken, "Missing return statement")
-		}
-	}
-
 	return statement
 }
 
@@ -228,32 +361,317 @@ func (parser *Parser) parseIfStatement(context *types.Context) *IfStatement {
 	parser.consume()
 
 	statement.StatementContext = types.ExtendContext(context)
+	narrowContext(statement.StatementContext, getNarrowedTypes(statement.Condition, context, true))
 	statement.Statement = parser.parseStatement(statement.StatementContext)
 
 	if parser.peek().Type == token.Else {
 		parser.consume()
 		parser.consume()
 		statement.AlternativeContext = types.ExtendContext(context)
+		narrowContext(statement.AlternativeContext, getNarrowedTypes(statement.Condition, context, false))
 		statement.Alternative = parser.parseStatement(statement.AlternativeContext)
 	}
 
 	return statement
 }
 
-func (parser *Parser) parseWhileStatement(context *types.Context) *WhileStatement {
+func (parser *Parser) parseMatchStatement(context *types.Context) *MatchStatement {
 
-	statement := &WhileStatement{WhileToken: parser.consume()}
+	matchExpression, isMatch := parser.parseMatchExpression(context).(*MatchExpression)
+	if !isMatch {
+		return nil
+	}
+	parser.checkMatchArms(matchExpression, context)
+
+	return &MatchStatement{Match: matchExpression}
+}
+
+func (parser *Parser) parseWhileStatement(context *types.Context, label *Identifier) *WhileStatement {
+
+	statement := &WhileStatement{WhileToken: parser.consume(), Label: label}
 
 	statement.Condition = parser.parseExpression(context, ExpressionLowest)
 	parser.getExpressionType(statement.Condition, context) // check type
 	parser.consume()
 
-	statement.StatementContext = types.ExtendContext(context)
+	statement.StatementContext = types.ExtendLoopContext(context, labelName(label))
+	statement.Statement = parser.parseStatement(statement.StatementContext)
+
+	return statement
+}
+
+func (parser *Parser) parseForStatement(context *types.Context, label *Identifier) Statement {
+
+	forToken := parser.consume()
+
+	if parser.current().Type == token.Ident && parser.peek().Type == token.In { // in
+		return parser.parseForInStatement(context, forToken, label)
+	}
+
+	statement := &ForStatement{ForToken: forToken, Label: label}
+	statement.LoopContext = types.ExtendContext(context)
+
+	if parser.current().Type != token.LBrace {
+		switch parser.current().Type {
+		case token.Semi:
+		case token.Let:
+			initializer := parser.parseLetStatement(statement.LoopContext)
+			if initializer == nil {
+				return nil
+			}
+			statement.Initializer = initializer
+		default:
+			statement.Initializer = parser.parseExpressionStatement(statement.LoopContext)
+		}
+		if parser.current().Type != token.Semi {
+			return nil
+		}
+
+		if parser.peek().Type != token.Semi {
+			parser.consume()
+			statement.Condition = parser.parseExpression(statement.LoopContext, ExpressionLowest)
+			if isInvalid(statement.Condition) {
+				return nil
+			}
+			parser.getExpressionType(statement.Condition, statement.LoopContext) // check type
+		}
+		if !parser.assertNext(token.Semi) {
+			return nil
+		}
+
+		if parser.peek().Type != token.LBrace {
+			parser.consume()
+			statement.Update = parser.parseExpression(statement.LoopContext, ExpressionLowest)
+			if isInvalid(statement.Update) {
+				return nil
+			}
+			parser.getExpressionType(statement.Update, statement.LoopContext) // check type
+		}
+		parser.consume()
+	}
+
+	statement.StatementContext = types.ExtendLoopContext(statement.LoopContext, labelName(label))
+	statement.Statement = parser.parseStatement(statement.StatementContext)
+
+	return statement
+}
+
+func (parser *Parser) parseForInStatement(context *types.Context, forToken *token.Token, label *Identifier) Statement {
+
+	identToken := parser.current()
+	statement := &ForInStatement{
+		ForToken: forToken,
+		Label:    label,
+		Variable: &Identifier{IdentToken: identToken, Value: identToken.Literal},
+	}
+	parser.consume()
+	parser.consume()
+	statement.Iterable = parser.parseExpression(context, ExpressionLowest)
+	if isInvalid(statement.Iterable) {
+		return nil
+	}
+	elementType := parser.getIterableElementType(statement.Iterable, context)
+	parser.consume()
+
+	statement.StatementContext = types.ExtendLoopContext(context, labelName(label))
+	statement.StatementContext.DefineMemberType(statement.Variable.Value, elementType)
 	statement.Statement = parser.parseStatement(statement.StatementContext)
 
 	return statement
 }
 
+func (parser *Parser) parseBreakStatement(context *types.Context) *BreakStatement {
+	statement := &BreakStatement{BreakToken: parser.current()}
+	statement.Label = parser.parseLoopLabel(context, statement.BreakToken)
+	parser.assertNext(token.Semi)
+	return statement
+}
+
+func (parser *Parser) parseContinueStatement(context *types.Context) *ContinueStatement {
+	statement := &ContinueStatement{ContinueToken: parser.current()}
+	statement.Label = parser.parseLoopLabel(context, statement.ContinueToken)
+	parser.assertNext(token.Semi)
+	return statement
+}
+
+func (parser *Parser) parseThrowStatement(context *types.Context) *ThrowStatement {
+	statement := &ThrowStatement{ThrowToken: parser.consume()}
+	statement.Value = parser.parseExpression(context, ExpressionLowest)
+	parser.assertNext(token.Semi)
+
+	valueType := parser.getExpressionType(statement.Value, context)
+	if !isNever(valueType) && !(&types.String{}).IsAssignable(valueType, context) &&
+		!types.Error.IsAssignable(valueType, context) {
+		parser.error(statement.Value.Token(), "Type '%s' cannot be thrown", valueType.ToString())
+	}
+	return statement
+}
+
+func (parser *Parser) parseTryStatement(context *types.Context) *TryStatement {
+
+	statement := &TryStatement{TryToken: parser.current()}
+	if !parser.assertNext(token.LBrace) {
+		return nil
+	}
+	statement.Body = parser.parseBlockStatement(context)
+
+	if parser.peek().Type == token.Catch {
+		parser.consume()
+		statement.CatchContext = types.ExtendContext(context)
+		if parser.peek().Type == token.LParen {
+			parser.consume()
+			if !parser.assertNext(token.Ident) {
+				return nil
+			}
+			identToken := parser.current()
+			statement.CatchVariable = &Identifier{IdentToken: identToken, Value: identToken.Literal}
+			statement.CatchContext.DefineMemberType(identToken.Literal, types.Error)
+			if !parser.assertNext(token.RParen) {
+				return nil
+			}
+		}
+		if !parser.assertNext(token.LBrace) {
+			return nil
+		}
+		statement.Catch = parser.parseBlockStatement(statement.CatchContext)
+	}
+
+	if parser.peek().Type == token.Finally {
+		parser.consume()
+		if !parser.assertNext(token.LBrace) {
+			return nil
+		}
+		statement.Finally = parser.parseBlockStatement(context)
+	}
+
+	if statement.Catch == nil && statement.Finally == nil {
+		parser.error(statement.TryToken, "Missing catch or finally block")
+	}
+	return statement
+}
+
+func (parser *Parser) parseImportStatement(context *types.Context) *ImportStatement {
+
+	statement := &ImportStatement{ImportToken: parser.current()}
+	if !parser.assertNext(token.StringLiteral) {
+		return nil
+	}
+	pathToken := parser.current()
+	statement.Path = &StringLiteral{LiteralToken: pathToken, Value: pathToken.Literal}
+
+	if parser.peek().Type == token.As {
+		parser.consume()
+		if !parser.assertNext(token.Ident) {
+			return nil
+		}
+		aliasToken := parser.current()
+		statement.Alias = &Identifier{IdentToken: aliasToken, Value: aliasToken.Literal}
+	}
+	if !parser.assertNext(token.Semi) {
+		return nil
+	}
+
+	statement.Module = parser.loadModule(statement.ImportToken, pathToken, context)
+	if statement.Module == nil {
+		return nil
+	}
+	moduleContext := statement.Module.Program.Context
+
+	if name, ok := context.ImportTypes(moduleContext); !ok {
+		parser.error(pathToken, "Cannot redefine '%s'", name)
+	}
+	if statement.Alias != nil {
+		statement.AliasType = &types.Struct{Fields: moduleContext.MemberTypes()}
+		for name := range statement.AliasType.Fields {
+			statement.AliasType.FieldNames = append(statement.AliasType.FieldNames, name)
+		}
+		sort.Strings(statement.AliasType.FieldNames)
+		if _, ok := context.DefineConstantType(statement.Alias.Value, statement.AliasType); !ok {
+			parser.error(statement.Alias.IdentToken, "Cannot redefine '%s'", statement.Alias.Value)
+		}
+	} else if name, ok := context.ImportMembers(moduleContext); !ok {
+		parser.error(pathToken, "Cannot redefine '%s'", name)
+	}
+
+	return statement
+}
+
+func (parser *Parser) loadModule(importToken *token.Token, pathToken *token.Token, context *types.Context) *Module {
+
+	path := pathToken.Literal
+	if importToken.File != nil && !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(*importToken.File), path)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		parser.error(pathToken, "Invalid module path '%s'", pathToken.Literal)
+		return nil
+	}
+
+	if parser.modules == nil {
+		parser.modules = &moduleStore{modules: make(map[string]*Module)}
+		if importToken.File != nil {
+			parser.modules.loading = []string{*importToken.File}
+		}
+	}
+	loading := parser.modules.loading
+	for i, loadingPath := range loading {
+		if loadingPath == path {
+			cycle := make([]string, 0)
+			for _, cyclePath := range append(loading[i:], path) {
+				cycle = append(cycle, filepath.Base(cyclePath))
+			}
+			parser.error(pathToken, "Import cycle (%s)", strings.Join(cycle, " -> "))
+			return nil
+		}
+	}
+	if module, ok := parser.modules.modules[path]; ok {
+		return module
+	}
+
+	moduleLexer, err := lexer.FromFile(path)
+	if err != nil {
+		parser.error(pathToken, "Cannot read module '%s'", pathToken.Literal)
+		return nil
+	}
+	moduleParser := New(moduleLexer)
+	moduleParser.modules = parser.modules
+
+	parser.modules.loading = append(loading, path)
+	program, errors := moduleParser.ParseProgram(context.Root())
+	parser.modules.loading = loading
+	parser.errors = append(parser.errors, errors...)
+
+	module := &Module{Path: path, Program: program}
+	parser.modules.modules[path] = module
+	return module
+}
+
+func (parser *Parser) parseLoopLabel(context *types.Context, keywordToken *token.Token) *Identifier {
+	var label *Identifier
+	if parser.peek().Type == token.Ident {
+		parser.consume()
+		labelToken := parser.current()
+		label = &Identifier{IdentToken: labelToken, Value: labelToken.Literal}
+	}
+
+	if !context.IsInLoop(labelName(label)) {
+		if label != nil {
+			parser.error(label.IdentToken, "Unknown loop label '%s'", label.Value)
+		} else {
+			parser.error(keywordToken, "Illegal %s statement outside of loop", keywordToken.Type.ToStringHumanReadable())
+		}
+	}
+	return label
+}
+
+func labelName(label *Identifier) string {
+	if label == nil {
+		return ""
+	}
+	return label.Value
+}
+
 func (parser *Parser) parseTypeDefinitionStatement(context *types.Context) *TypeDefinitionStatement {
 
 	if !parser.assertNext(token.Ident) {
@@ -263,16 +681,33 @@ func (parser *Parser) parseTypeDefinitionStatement(context *types.Context) *Type
 	name := identToken.Literal
 	ident := &Identifier{IdentToken: identToken, Value: name}
 
+	var typeParameters []*types.TypeParameter
+	typeContext := context
+	if parser.peek().Type == token.LT {
+		parser.consume()
+		typeParameters, typeContext = parser.parseTypeParameterList(context)
+		if typeParameters == nil {
+			return nil
+		}
+	}
+
 	if !parser.assertNext(token.Define) {
 		return nil
 	}
 
 	parser.consume()
 	statement := &TypeDefinitionStatement{IdentToken: identToken, Name: ident}
-	statement.Type = parser.parseType(context, TypeLowest)
+	statement.Type = parser.parseType(typeContext, TypeLowest)
+	if enumType, isEnum := statement.Type.(*types.Enum); isEnum && enumType.Name == "" {
+		enumType.Name = name
+		enumType.TypeParameters = typeParameters
+	}
+	if typeParameters != nil {
+		statement.Type = &types.Generic{Name: name, TypeParameters: typeParameters, Type: statement.Type}
+	}
 
 	switch name {
-	case types.TypeNull, types.TypeVoid, types.TypeString, types.TypeInt, types.TypeFloat, types.TypeBool:
+	case types.TypeNull, types.TypeVoid, types.TypeString, types.TypeInt, types.TypeFloat, types.TypeBool, types.TypeMap:
 		parser.error(identToken, "Cannot re-declare primitive '%s'", name)
 	default:
 		if _, ok := context.DefineType(name, statement.Type); !ok {
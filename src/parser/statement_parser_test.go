@@ -6,6 +6,8 @@ import (
 	"bananascript/src/types"
 	"github.com/google/go-cmp/cmp"
 	"gotest.tools/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -20,6 +22,16 @@ func TestStatementParser(t *testing.T) {
 		},
 	)
 
+	assertStatement(t,
+		"const b: float = 1.5;",
+		&LetStatement{
+			Name:     &Identifier{Value: "b"},
+			Type:     &types.Float{},
+			Value:    &FloatLiteral{Value: 1.5},
+			Constant: true,
+		},
+	)
+
 	assertStatement(t,
 		"if a == 5 || a == 3 println(\"test\");",
 		&IfStatement{
@@ -47,6 +59,20 @@ func TestStatementParser(t *testing.T) {
 		},
 	)
 
+	assertStatement(t,
+		"for c in \"abc\" println(c);",
+		&ForInStatement{
+			Variable: &Identifier{Value: "c"},
+			Iterable: &StringLiteral{Value: "abc"},
+			Statement: &ExpressionStatement{
+				Expression: &CallExpression{
+					Function:  &Identifier{Value: "println"},
+					Arguments: []Expression{&Identifier{Value: "c"}},
+				},
+			},
+		},
+	)
+
 	assertStatement(t,
 		"type optionalString := string?;",
 		&TypeDefinitionStatement{
@@ -62,8 +88,202 @@ func TestStatementParser(t *testing.T) {
 	assertError(t, "fn noReturn() string {}")
 	assertError(t, "{ type test := iface { abc: fn() void; }; let a: test = 2; }")
 
+	assertError(t, "let a := [1, \"2\"];")
+	assertError(t, "let a := [];")
+	assertError(t, "let a := [[]];")
+	assertError(t, "let a := { \"a\": [] };")
+	assertError(t, "let a := ([], 1);")
+	assertError(t, "{ let a := [1]; a[0] = 1.5; }")
+	assertError(t, "{ let a := [1, 2]; let b: [](int?) = a; }")
+	assertError(t, "let a: []int | string = [\"s\"];")
+	assertError(t, "{ let a: []int = [1, \"a\"]; let b: map[string]int = { \"a\": null }; }")
+	assertError(t, "{ let a := [1]; a[\"0\"]; }")
+	assertError(t, "{ let a := 1; a[0]; }")
+	assertError(t, "let a := {};")
+	assertError(t, "let a := { 1: 2, \"3\": 4 };")
+	assertError(t, "let a := { [1]: 2 };")
+	assertError(t, "{ let a := { \"a\": 1 }; a[1] = 2; }")
+	assertError(t, "{ let a := { \"a\": 1 }; let b: map[string](int?) = a; }")
+	assertError(t, "{ type p := struct { x: int; }; let a := p { }; }")
+	assertError(t, "{ type a := struct { x: int; }; type b := struct { x: int?; }; let p := a { x: 1 }; let q: b = p; }")
+	assertError(t, "{ type p := struct { x: int; }; let a := p { x: 1, y: 2 }; }")
+	assertError(t, "{ type p := struct { x: int; }; let a := p { x: 1 }; a.x = true; }")
+	assertError(t, "{ type p := struct { x: int; x: int; }; }")
+	assertError(t, "let f := fn(x: int) int { };")
+	assertError(t, "{ let f := fn(x: int) string { return x; }; }")
+	assertError(t, "{ let f := fn(x: int) int { return x; }; f(\"a\"); }")
+	assertError(t, "let f: fn() int = fn() string { return \"x\"; };")
+	assertError(t, "{ type anything := iface { }; let f: fn(anything) void = fn(x: int) { }; }")
+	assertError(t, "for x in 5 {}")
+	assertError(t, "for x in 0..1.5 {}")
+	assertError(t, "for ;;")
+	assertError(t, "fn f() { for")
+	assertError(t, "lbl: for")
+	assertError(t, "for x in")
+	assertError(t, "while")
+	assertError(t, "{ for let i := 0; i < 3; i++ {} i; }")
+	assertError(t, "const a;")
+	assertError(t, "{ const a := 1; a = 2; }")
+	assertError(t, "{ const a := 1; a++; }")
+	assertError(t, "{ const a := 1; fn f() { --a; } }")
+	assertError(t, "break;")
+	assertError(t, "while true { let f := fn() { continue; }; }")
+	assertError(t, "outer: while true { break inner; }")
+	assertError(t, "label: let a := 1;")
+	assertError(t, "a: while true { a: for x in [1] { break a; } }", "Label 'a' is already defined")
+	assertError(t, "let a := 1.5 & 2;")
+	assertError(t, "let a := 1 << 2.0;")
+	assertError(t, "let a := ~true;")
+	assertError(t, "{ let a := 1; a += 1.5; }")
+	assertError(t, "{ let a := \"a\"; a -= 1; }")
+	assertError(t, "{ let a := 1; a ??= 2; }")
+	assertError(t, "{ const a := 1; a *= 2; }")
+	assertError(t, "let a := true ? 1 : \"1\";")
+	assertError(t, "let a: int = true ? 1 : null;")
+	assertError(t, "let a := true ? 1;")
+	assertError(t, "let a := match 1 { 1 => \"a\" };")
+	assertError(t, "let a := match 1 { \"1\" => 1, _ => 2 };")
+	assertError(t, "let a := match 1 { _ => 1, 2 => 2 };")
+	assertError(t, "let a := match 1 { 1 => 1, _ => \"a\" };")
+	assertError(t, "fn f(b: bool) int { match b { true => { return 1; } } }")
+	assertError(t, "fn f<T>(a: T) T { return 1; }")
+	assertError(t, "{ fn f<T>() T? { return null; } f(); }")
+	assertError(t, "{ fn f<T: iface { x: int; }>(a: T) int { return a.x; } f(1); }",
+		"Type 'int' does not satisfy constraint 'iface { x: int; }' of 'T'")
+	assertError(t, "{ fn f<T: iface { x: int; }>() {} f(); }", "Cannot infer type parameter 'T' satisfying 'iface { x: int; }'")
+	assertError(t, "{ fn f<T>(a: T) { a.x; } }")
+	assertError(t, "fn f<T, T>() {}")
+	assertError(t, "{ type box<T> := struct { value: T; }; let b: box<int, int>? = null; }")
+	assertError(t, "{ type box<T> := struct { value: T; }; let b: box? = null; }")
+	assertError(t, "{ type box<T: iface { x: int; }> := []T; let b: box<int> = []; }")
+	assertError(t, "{ type box<T> := struct { value: T; }; let b := box<int> { value: \"a\" }; }")
+	assertError(t, "{ type box<T> := struct { value: T; }; let b: box<box<int>> = box<box<string>> { value: box<string> { value: \"a\" } }; }")
+	assertError(t, "{ let u: int | string = 1; let i: int = u; }")
+	assertError(t, "{ let u: int | string = 1; let s := match u { is int => 1 }; }")
+	assertError(t, "{ let u: int | string = 1; if u is int { u = \"a\"; let i: int = u; } }")
+	assertError(t, "{ let u: int | string | bool = 1; if u is int || u is string { let i: int = u; } }")
+	assertError(t, "fn f(x: int?) int { if x == null { return x; } return 0; }")
+	assertError(t, "fn f(x: int?) int { if x != null { x = null; return x; } return 0; }")
+	assertError(t, "{ let x: string? = \"a\"; fn clear() { x = null; } if x != null { clear(); x.length(); } }")
+	assertError(t, "{ let x: int? = 1; fn g() {} fn f() int { if x != null { g(); return x; } return 0; } }")
+	assertError(t, "fn f(x: int?, c: bool) int { if x == null { return 0; } if c { x = null; } return x; }")
+	assertError(t, "fn f(x: int?) bool { return x != null || x > 1; }")
+	assertError(t, "fn f(x: int?) int { if true { return x; } if x == null { return 0; } return x; }")
+	assertError(t, "fn f() { return 1; }")
+	assertError(t, "{ let a := 1; let b := a ?? 2; }")
+	assertError(t, "{ let (a, b) := (1, 2, 3); }")
+	assertError(t, "{ let (a, b) := 1; }")
+	assertError(t, "{ let t := (1, 2); t[0] = 3; }")
+	assertError(t, "{ let t := (1, 2); let i := 0; let a := t[i]; }")
+	assertError(t, "{ let t := (1, 2); let a := t[2]; }")
+	assertError(t, "{ const (a, b) := (1, 2); a = 3; }")
+	assertError(t, "{ type a := enum { x; }; type b := enum { x; }; let v: a = b.x; }")
+	assertError(t, "{ type a := enum { x; y; }; let v := a.z; }")
+	assertError(t, "{ type r := enum { ok(int); err(string); }; let v := r.ok(\"a\"); }")
+	assertError(t, "{ type a := enum { x; y; }; let v := match a.x { a.x => 1 }; }")
+	assertError(t, "{ type r := enum { ok(int); err(string); }; let v := match r.ok(1) { r.ok(a, b) => 1, _ => 2 }; }")
+	assertError(t, "{ type r := enum { ok(int); err(int); }; let v := match r.ok(1) { r.ok(a) | r.err(a) => a }; }")
+	assertError(t, "{ type o<T> := enum { some(T); none; }; let v: o<int> = o<string>.none; }",
+		"Type 'o<string>' is not assignable to 'o<int>'")
+	assertError(t, "{ type r<T> := enum { ok(T); err(string); }; let v: r<int> = r<string>.ok(\"a\"); }",
+		"Type 'r<string>' is not assignable to 'r<int>'")
+	assertError(t, "{ type a := iface { x: int; }; type b := iface { y: int; }; type p := struct { x: int; }; let v: a & b = p { x: 1 }; }")
+	assertError(t, "{ type a := iface { x: int; }; type b := iface { x: bool; }; let v: a & b? = null; }")
+	assertError(t, "{ let a: int? = null; let b := a ?? \"b\"; }")
+	assertError(t, "{ fn (int)::half() float { return this / 2.0; } let a: int? = null; let b: float = a?.half(); }")
+	assertError(t, "{ type p := struct { x: int; }; let a: p? = null; a?.x = 1; }")
+	assertError(t, "{ let a := 1; let s := \"${a +}\"; }")
+	assertError(t, "let s := \"${}\";")
+	assertError(t, "let s := \"a ${1} b ${} c\";")
+	assertError(t, "{ let s := \"${b}\"; }")
+	assertError(t, "{ fn f() {} let s := \"${f()}\"; }")
+	assertError(t, "{ let a: int = \"${1}\"; }")
+	assertError(t, "{ throw 5; }")
+
+	moduleDir := t.TempDir()
+	util := filepath.Join(moduleDir, "util.bs")
+	assert.NilError(t, os.WriteFile(filepath.Join(moduleDir, "a.bs"), []byte("import \"b.bs\";"), 0644))
+	assert.NilError(t, os.WriteFile(filepath.Join(moduleDir, "b.bs"), []byte("import \"a.bs\";"), 0644))
+	assert.NilError(t, os.WriteFile(util, []byte("type p := struct { x: int; }; const one := 1; "+
+		"fn (int)::inc() int { return this + 1; }"), 0644))
+	assertProgramError(t, "import \""+filepath.Join(moduleDir, "a.bs")+"\";")
+	assertProgramError(t, "import \""+filepath.Join(moduleDir, "missing.bs")+"\";")
+	assertProgramError(t, "import \""+util+"\"; one = 2;")
+	assertProgramError(t, "import \""+util+"\"; import \""+util+"\";")
+	assertProgramError(t, "import \""+util+"\" as u; let a := one;")
+	assertError(t, "{ import \""+util+"\"; }")
+	assertProgramNoError(t, "import \""+util+"\"; let a: p = p { x: one.inc() };")
+	assertProgramNoError(t, "import \""+util+"\" as u; let a: p = p { x: u.one }; let one := \"shadowed\";")
+	assertError(t, "{ try { } }")
+	assertError(t, "{ try { } catch (e) { let k: int = e.kind; } }")
+	assertError(t, "fn f() int { try { return 1; } catch { } }")
+	assertError(t, "fn f() int { throw \"x\"; return 1; }")
+	assertError(t, "{ fn c(a: int = \"x\") {} }")
+	assertError(t, "{ fn d(a: int = 1, b: int) {} }")
+	assertError(t, "{ fn d(a: int, b: int = 1) {} d(b: 2); }")
+	assertError(t, "{ fn d(a: int) {} d(1, a: 2); }")
+	assertError(t, "{ fn d(a: int, b: int) {} d(a: 1, 2); }")
+	assertError(t, "{ fn d(a: int) {} d(x: 1); }")
+	assertError(t, "{ fn d(a: int) {} let f: fn(int) void = d; f(a: 1); }")
+	assertError(t, "{ fn v(a: ...int, b: int) {} }")
+	assertError(t, "{ fn v(a: ...int = 1) {} }")
+	assertError(t, "{ fn v(a: int, b: ...int) {} v(); v(1, 2, \"3\"); }")
+	assertError(t, "{ fn v(a: ...int) {} v(a: 1); }")
+	assertError(t, "{ fn v(a: int) {} let f: fn(...int) void = v; }")
+
 	assertNoError(t, "{ type str := string; let a: str = \"test\"; }")
 	assertNoError(t, "{ type test := iface { }; let a: test = 0; let b: test = \"\"; let c: test = false; }")
+	assertNoError(t, "{ let a: [][]int = [[1], []]; a[0][0] = 2; }")
+	assertNoError(t, "{ let a := [[], [1]]; let b: map[string][]int = { \"a\": [] }; a[0][0] = 2; }")
+	assertNoError(t, "{ let a: map[string][]int = {}; a[\"x\"] = [1]; let b: int = a[\"x\"][0]; }")
+	assertNoError(t, "{ type p := struct { x: int; y: int?; }; let a := p { x: 1 }; a.y = a.x; }")
+	assertNoError(t, "{ type p := struct { x: int; }; type hasX := iface { x: int; }; let a: hasX = p { x: 1 }; }")
+	assertNoError(t, "{ let y := 2; let f: fn(int) int = fn(x: int) int { return x * y; }; let z: int = f(1); }")
+	assertNoError(t, "{ type anything := iface { }; let f: fn(int) int? = fn(x: anything) int { return 1; }; fn ap<T, U>(x: T, f: fn(T) U) U { return f(x); } let g: int = ap(1, fn(x: anything) int { return 1; }); }")
+	assertNoError(t, "{ let s := 0; for let i := 0; i < 3; i++ { s = s + i; } for i in 0..3 { s = s + i; } }")
+	assertNoError(t, "{ const a := [1]; a[0] = 2; { let a := 3; a = 4; } }")
+	assertNoError(t, "outer: for i in 0..3 { while true { if i == 1 { continue outer; } break outer; } }")
+	assertNoError(t, "{ for x in [1, 2] { let y: int = x; } for k in { \"a\": 1 } { let l: string = k; } }")
+	assertNoError(t, "{ let a: int = 7 % 2 ** 3 & ~1 | 1 << 2 ^ 3 >> 1; let b: float = 7.5 % 2 ** 0.5; }")
+	assertNoError(t, "{ let a: int? = true ? 1 : null; let b: [](int?) = [null, 1]; let c: string = a == null ? \"\" : \"a\"; }")
+	assertNoError(t, "fn f(b: bool?) int { match b { true => { return 1; } false | null => { return 0; } } }")
+	assertNoError(t, "{ type anything := iface { }; fn f(v: anything) int { return match v { is int => v + 1, is bool => v ? 1 : 0, _ => 0 }; } }")
+	assertNoError(t, "{ fn first<T>(a: T, b: T) T { return a; } let a: int = first(1, 2); let b: int? = first(1, null); }")
+	assertNoError(t, "{ type p := struct { x: int; y: int; }; fn f<T: iface { x: int; }>(a: T) T { a.x; return a; } let y: int = f(p { x: 1, y: 2 }).y; }")
+	assertNoError(t, "{ let id := fn<T>(x: T) []T { return [x]; }; let xs: []string = id(\"a\"); }")
+	assertNoError(t, "{ type pair<A, B> := struct { a: A; b: B; }; let p: pair<int, string> = pair<int, string> { a: 1, b: \"b\" }; let b: string = p.b; }")
+	assertNoError(t, "{ type box<T> := struct { value: T; }; type has<T> := iface { value: T; }; let b: has<box<int>> = box<box<int>> { value: box<int> { value: 1 } }; }")
+	assertNoError(t, "{ type box<T> := struct { value: T; }; fn unbox<T>(b: box<T>) T { return b.value; } let v: int = unbox(box<int> { value: 1 }); }")
+	assertNoError(t, "{ let xs: [](int | string) = [1, \"a\"]; let m: map[string](int?) = { \"a\": 1, \"b\": null }; xs = [[1][0]]; fn f(ys: [](int?)) {} f([null]); }")
+	assertNoError(t, "{ let u: int | string = 1; if u is int { let i: int = u; } else { let s: string = u; } }")
+	assertNoError(t, "{ let u: int | string | bool = 1; if !(u is int) && !(u is bool) { let s: string = u; } }")
+	assertNoError(t, "{ let u: (int | string)? = null; let s := match u { null => 0, is int => u, is string => 1 }; }")
+	assertNoError(t, "fn f(x: int?) int { if x != null { return x; } else { let n: int? = x; } return 0; }")
+	assertNoError(t, "fn f(x: int?) int { if x == null { return 0; } let y: int = x; return x + y; }")
+	assertNoError(t, "fn f(xs: [](int?)) int { let s := 0; for x in xs { if null == x { continue; } s += x; } return s; }")
+	assertNoError(t, "fn f(x: int?, y: int?) bool { return x != null && y != null && x > y || x == null; }")
+	assertNoError(t, "fn f(x: int?) int { return x == null ? 0 : x; }")
+	assertNoError(t, "fn f(x: int?) int? { if x == null { x = 1; } else { x = x + 1; } if x != null { x = null; } return x; }")
+	assertNoError(t, "{ fn g() {} fn f(x: int?) int { if x != null { g(); return x; } return 0; } }")
+	assertNoError(t, "{ fn g() {} const x: int? = 1; fn f() int { if x != null { g(); return x; } return 0; } }")
+	assertNoError(t, "fn f(cur: int?) int { if cur == null { return 0; } let v := cur; cur = null; cur ??= v + 1; return v; }")
+	assertNoError(t, "{ fn (int)::half() float { return this / 2.0; } let a: int? = null; let b: int = a ?? 1; let c: int? = a ?? null; let d: float? = a?.half(); }")
+	assertNoError(t, "{ type p := struct { x: int; }; let a: p? = null; let x: int = a?.x ?? 0; }")
+	assertNoError(t, "{ type a := iface { x: int; }; type b := iface { y: int; }; type p := struct { x: int; y: int; }; let v: a & b = p { x: 1, y: 2 }; let s: int = v.x + v.y; }")
+	assertNoError(t, "{ type a := enum { x; y; }; let v: a = a.x; let b: bool = v == a.y; let i: int = match v { a.x => 1, a.y => 2 }; }")
+	assertNoError(t, "{ type r<T> := enum { ok(T); err(string); }; fn f(v: r<int>) int { return match v { r<int>.ok(n) => n, r<int>.err(_) => 0 }; } }")
+	assertNoError(t, "{ type r<T> := enum { ok(T); err(string); }; fn get<T>(v: r<T>, fallback: T) T { match v { r<T>.ok(n) => { return n; } r<T>.err(_) => { return fallback; } } } let i: int = get(r<int>.ok(1), 2); }")
+	assertNoError(t, "{ fn divmod(a: int, b: int) (int, int) { return (a / b, a % b); } let (q, r) := divmod(7, 2); let s: int = q + r; }")
+	assertNoError(t, "{ let t: (int?, string) = (null, \"a\"); let (_, s): (int?, string) = t; let u: string = s + t[1]; }")
+	assertNoError(t, "{ let a := 1; let s: string = \"${a} + ${a * 2} = ${\"${a + a * 2}\"}\"; }")
+	assertNoError(t, "fn f(x: int?) int { if x == null { throw \"null\"; } return x; }")
+	assertNoError(t, "fn f() int { try { return 1; } catch (e) { throw e; } finally { } }")
+	assertNoError(t, "{ try { } catch (e) { let m: string = e.kind + e.message; let l: int? = e.line; } }")
+	assertNoError(t, "{ for i in 0..3 { try { break; } finally { continue; } } }")
+	assertNoError(t, "{ let s := \"a\"; s += 1; let f := 1.5; f *= 2; let xs := [1]; xs[0] %= 2; let o: int? = null; let i: int = o ??= 1; }")
+	assertNoError(t, "{ fn d(a: int, b: int = 1, c: bool = false) int { return a + b; } let x: int = d(1) + d(1, 2) + d(c: true, a: 1); let f: fn(int) int = d; }")
+	assertNoError(t, "{ fn v(a: int = 0, b: ...int) []int { return b; } let x: []int = v(); v(1, 2, 3); let f: fn(int, ...int) []int = v; let g: fn(int, int) []int = v; }")
+	assertNoError(t, "{ fn v<T>(a: ...T) T? { return a[0]; } let x: int? = v(1, null); }")
 }
 
 func assertStatement(t *testing.T, input string, expected Statement) {
@@ -92,9 +312,31 @@ func parse(input string) *Parser {
 	return theParser
 }
 
-func assertError(t *testing.T, input string) {
+func assertError(t *testing.T, input string, messages ...string) {
 	theParser := parse(input)
 	assert.Assert(t, len(theParser.errors) > 0, input)
+
+	errorMessages := make([]string, len(theParser.errors))
+	for i, err := range theParser.errors {
+		errorMessages[i] = err.Message
+	}
+	for _, message := range messages {
+		found := false
+		for _, errorMessage := range errorMessages {
+			found = found || errorMessage == message
+		}
+		assert.Assert(t, found, "\ninput: %s\nexpected: %s\nerrors: %v", input, message, errorMessages)
+	}
+}
+
+func assertProgramError(t *testing.T, input string) {
+	_, errors := New(lexer.FromCode(input)).ParseProgram(types.NewContext())
+	assert.Assert(t, len(errors) > 0, input)
+}
+
+func assertProgramNoError(t *testing.T, input string) {
+	_, errors := New(lexer.FromCode(input)).ParseProgram(types.NewContext())
+	assert.Assert(t, len(errors) == 0, "\ninput: %s\nerrors: %v", input, errors)
 }
 
 func assertNoError(t *testing.T, input string) {
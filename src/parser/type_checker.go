@@ -13,6 +13,13 @@ func (parser *Parser) getExpressionType(expression Expression, context *types.Co
 		return parser.getPrefixExpressionType(expression, context)
 	case *InfixExpression:
 		return parser.getInfixExpressionType(expression, context)
+	case *ConditionalExpression:
+		return parser.getConditionalExpressionType(expression, context)
+	case *IsExpression:
+		parser.getExpressionType(expression.Expression, context) // check type
+		return &types.Bool{}
+	case *MatchExpression:
+		return parser.getMatchExpressionType(expression, context)
 	case *AssignmentExpression:
 		return parser.getAssignmentExpressionType(expression, context)
 	case *CallExpression:
@@ -21,8 +28,28 @@ func (parser *Parser) getExpressionType(expression Expression, context *types.Co
 		return parser.getIncrementExpressionType(expression, context)
 	case *MemberAccessExpression:
 		return parser.getMemberAccessExpressionType(expression, context)
+	case *IndexExpression:
+		return parser.getIndexExpressionType(expression, context)
+	case *IndexAssignmentExpression:
+		return parser.getIndexAssignmentExpressionType(expression, context)
+	case *ArrayLiteral, *MapLiteral:
+		return parser.getExpectedExpressionType(expression, nil, context)
+	case *FunctionLiteral:
+		return expression.FunctionType
+	case *StructLiteral:
+		return parser.getStructLiteralType(expression, context)
+	case *EnumLiteral:
+		return parser.getEnumLiteralType(expression, context)
+	case *TupleLiteral:
+		return parser.getTupleLiteralType(expression, context)
+	case *EnumPattern:
+		return expression.Type
+	case *MemberAssignmentExpression:
+		return parser.getMemberAssignmentExpressionType(expression, context)
 	case *StringLiteral:
 		return &types.String{}
+	case *InterpolatedStringLiteral:
+		return parser.getInterpolatedStringLiteralType(expression, context)
 	case *IntegerLiteral:
 		return &types.Int{}
 	case *FloatLiteral:
@@ -40,6 +67,24 @@ func (parser *Parser) getExpressionType(expression Expression, context *types.Co
 	return &types.Never{}
 }
 
+// getExpectedExpressionType types array and map literals based on the type they are assigned to, so that e.g.
+// [1, "a"] can be a [](int | string). Other expressions are typed as usual.
+func (parser *Parser) getExpectedExpressionType(expression Expression, expectedType types.Type, context *types.Context) types.Type {
+	switch expression := expression.(type) {
+	case *ArrayLiteral:
+		if expression.Type == nil {
+			expression.Type = parser.getArrayLiteralType(expression, expectedArrayType(expectedType), context)
+		}
+		return expression.Type
+	case *MapLiteral:
+		if expression.Type == nil {
+			expression.Type = parser.getMapLiteralType(expression, expectedMapType(expectedType), context)
+		}
+		return expression.Type
+	}
+	return parser.getExpressionType(expression, context)
+}
+
 func (parser *Parser) getIdentifierType(identifier *Identifier, context *types.Context) types.Type {
 	theType, ok := context.GetMemberType(identifier.Value)
 	if !ok {
@@ -66,6 +111,10 @@ func (parser *Parser) getPrefixExpressionType(prefixExpression *PrefixExpression
 		case *types.Float:
 			return &types.Float{}
 		}
+	case token.Tilde:
+		if _, isInt := currentType.(*types.Int); isInt {
+			return &types.Int{}
+		}
 	}
 
 	parser.error(prefixExpression.PrefixToken, "Type mismatch: %s%s", prefixExpression.Operator.ToString(),
@@ -74,11 +123,35 @@ func (parser *Parser) getPrefixExpressionType(prefixExpression *PrefixExpression
 }
 
 func (parser *Parser) getInfixExpressionType(infixExpression *InfixExpression, context *types.Context) types.Type {
-	leftType, rightType := parser.getExpressionType(infixExpression.Left, context), parser.getExpressionType(infixExpression.Right, context)
+	leftType, rightType := parser.getExpressionType(infixExpression.Left, context), parser.getExpressionType(infixExpression.Right, getRightContext(infixExpression, context))
 	if isNever(leftType) || isNever(rightType) {
 		return &types.Never{}
 	}
+	if infixExpression.Operator == token.Nullish {
+		return parser.getNullishExpressionType(infixExpression.OperatorToken, leftType, rightType, context)
+	}
+	return parser.getInfixOperatorType(infixExpression.OperatorToken, infixExpression.Operator, leftType, rightType)
+}
+
+func (parser *Parser) getNullishExpressionType(operatorToken *token.Token, leftType types.Type, rightType types.Type, context *types.Context) types.Type {
+	switch leftType := leftType.(type) {
+	case *types.Null:
+		return rightType
+	case *types.Optional:
+		commonType, ok := types.CommonSupertype(leftType.Base, rightType, context)
+		if !ok {
+			parser.error(operatorToken, "Types '%s' and '%s' have no common supertype",
+				leftType.Base.ToString(), rightType.ToString())
+			return &types.Never{}
+		}
+		return commonType
+	default:
+		parser.error(operatorToken, "Type '%s' is not optional", leftType.ToString())
+		return &types.Never{}
+	}
+}
 
+func (parser *Parser) getInfixOperatorType(operatorToken *token.Token, operator token.Type, leftType types.Type, rightType types.Type) types.Type {
 	_, leftIsInt := leftType.(*types.Int)
 	_, leftIsFloat := leftType.(*types.Float)
 	_, leftIsString := leftType.(*types.String)
@@ -86,13 +159,17 @@ func (parser *Parser) getInfixExpressionType(infixExpression *InfixExpression, c
 	_, rightIsFloat := rightType.(*types.Float)
 	_, rightIsString := rightType.(*types.String)
 
-	switch infixExpression.Operator {
+	switch operator {
 	case token.EQ, token.NEQ, token.LogicalOr, token.LogicalAnd:
 		return &types.Bool{}
 	case token.LT, token.GT, token.LTE, token.GTE:
 		if (leftIsInt || leftIsFloat) && (rightIsInt || rightIsFloat) {
 			return &types.Bool{}
 		}
+	case token.DotDot:
+		if leftIsInt && rightIsInt {
+			return &types.Array{ElementType: &types.Int{}}
+		}
 	case token.Plus:
 		if leftIsString || rightIsString {
 			return &types.String{}
@@ -103,7 +180,7 @@ func (parser *Parser) getInfixExpressionType(infixExpression *InfixExpression, c
 				return &types.Float{}
 			}
 		}
-	case token.Minus, token.Slash, token.Star:
+	case token.Minus, token.Slash, token.Star, token.Percent, token.Power:
 		if (leftIsInt || leftIsFloat) && (rightIsInt || rightIsFloat) {
 			if leftIsInt && rightIsInt {
 				return &types.Int{}
@@ -111,22 +188,202 @@ func (parser *Parser) getInfixExpressionType(infixExpression *InfixExpression, c
 				return &types.Float{}
 			}
 		}
+	case token.Amp, token.Pipe, token.Caret, token.ShiftLeft, token.ShiftRight:
+		if leftIsInt && rightIsInt {
+			return &types.Int{}
+		}
 	}
 
-	parser.error(infixExpression.OperatorToken, "Type mismatch: %s %s %s", leftType.ToString(),
-		infixExpression.Operator.ToString(), rightType.ToString())
+	parser.error(operatorToken, "Type mismatch: %s %s %s", leftType.ToString(), operator.ToString(),
+		rightType.ToString())
 	return &types.Never{}
 }
 
+func (parser *Parser) getConditionalExpressionType(conditionalExpression *ConditionalExpression, context *types.Context) types.Type {
+	parser.getExpressionType(conditionalExpression.Condition, context) // check type
+	consequenceType := parser.getExpressionType(conditionalExpression.Consequence,
+		extendNarrowedContext(conditionalExpression.Condition, context, true))
+	alternativeType := parser.getExpressionType(conditionalExpression.Alternative,
+		extendNarrowedContext(conditionalExpression.Condition, context, false))
+	if isNever(consequenceType) || isNever(alternativeType) {
+		return &types.Never{}
+	}
+
+	commonType, ok := types.CommonSupertype(consequenceType, alternativeType, context)
+	if !ok {
+		parser.error(conditionalExpression.ColonToken, "Types '%s' and '%s' have no common supertype",
+			consequenceType.ToString(), alternativeType.ToString())
+		return &types.Never{}
+	}
+	return commonType
+}
+
+func (parser *Parser) getMatchExpressionType(matchExpression *MatchExpression, context *types.Context) types.Type {
+	armTypes := parser.checkMatchArms(matchExpression, context)
+	for _, arm := range matchExpression.Arms {
+		if _, isBlock := arm.Body.(*BlockStatement); isBlock {
+			parser.doesReturn(arm.Body) // check unreachable code
+		}
+	}
+
+	if !matchExpression.Exhaustive {
+		parser.error(matchExpression.MatchToken, "Match is not exhaustive")
+		return &types.Never{}
+	}
+
+	var commonType types.Type = &types.Never{}
+	for i, armType := range armTypes {
+		supertype, ok := types.CommonSupertype(commonType, armType, context)
+		if !ok {
+			parser.error(matchExpression.Arms[i].ArrowToken, "Types '%s' and '%s' have no common supertype",
+				commonType.ToString(), armType.ToString())
+			return &types.Never{}
+		}
+		commonType = supertype
+	}
+	return commonType
+}
+
+func (parser *Parser) checkMatchArms(matchExpression *MatchExpression, context *types.Context) []types.Type {
+	subjectType := parser.getExpressionType(matchExpression.Subject, context)
+	armTypes := make([]types.Type, 0)
+
+	for i, arm := range matchExpression.Arms {
+		if isMatchExhaustive(subjectType, matchExpression.Arms[:i], context) {
+			parser.error(arm.ArrowToken, "Unreachable match arm")
+		}
+		for _, pattern := range arm.Patterns {
+			patternType := parser.getExpressionType(pattern, context)
+			if !isNever(subjectType) && !isNever(patternType) && !subjectType.IsAssignable(patternType, context) {
+				parser.error(pattern.Token(), "Type '%s' cannot match '%s'", patternType.ToString(),
+					subjectType.ToString())
+			}
+		}
+
+		if expressionStatement, isExpression := arm.Body.(*ExpressionStatement); isExpression {
+			armTypes = append(armTypes, parser.getExpressionType(expressionStatement.Expression, arm.Context))
+		} else {
+			armTypes = append(armTypes, &types.Void{})
+		}
+	}
+
+	matchExpression.Exhaustive = isNever(subjectType) || isMatchExhaustive(subjectType, matchExpression.Arms, context)
+	return armTypes
+}
+
+func isMatchExhaustive(subjectType types.Type, arms []*MatchArm, context *types.Context) bool {
+	for _, arm := range arms {
+		if arm.Wildcard || arm.TypePattern != nil && arm.TypePattern.IsAssignable(subjectType, context) {
+			return true
+		}
+	}
+
+	switch subjectType := subjectType.(type) {
+	case *types.Optional:
+		return isMatchExhaustive(&types.Null{}, arms, context) && isMatchExhaustive(subjectType.Base, arms, context)
+	case *types.Union:
+		for _, member := range subjectType.Types {
+			if !isMatchExhaustive(member, arms, context) {
+				return false
+			}
+		}
+		return true
+	case *types.Null:
+		return hasMatchPattern(arms, func(pattern Expression) bool {
+			_, isNull := pattern.(*NullLiteral)
+			return isNull
+		})
+	case *types.Bool:
+		return hasMatchPattern(arms, func(pattern Expression) bool {
+			literal, isBoolean := pattern.(*BooleanLiteral)
+			return isBoolean && literal.Value
+		}) && hasMatchPattern(arms, func(pattern Expression) bool {
+			literal, isBoolean := pattern.(*BooleanLiteral)
+			return isBoolean && !literal.Value
+		})
+	case *types.Enum:
+		for _, variant := range subjectType.VariantNames {
+			if !hasMatchPattern(arms, func(pattern Expression) bool {
+				enumPattern, isEnumPattern := pattern.(*EnumPattern)
+				return isEnumPattern && enumPattern.Variant.Value == variant && enumPattern.Type.IsAssignable(subjectType, context)
+			}) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func hasMatchPattern(arms []*MatchArm, predicate func(Expression) bool) bool {
+	for _, arm := range arms {
+		for _, pattern := range arm.Patterns {
+			if predicate(pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (parser *Parser) getAssignmentExpressionType(assignmentExpression *AssignmentExpression, context *types.Context) types.Type {
-	leftType, rightType := parser.getExpressionType(assignmentExpression.Name, context), parser.getExpressionType(assignmentExpression.Expression, context)
+	name := assignmentExpression.Name.Value
+	leftType := parser.getExpressionType(assignmentExpression.Name, context)
+	assignToken := assignmentExpression.AssignToken
+	reassigned := assignToken.Type == token.Assign || assignToken.Type == token.NullishAssign
+	if declaredType, ok := context.GetDeclaredMemberType(name); ok && reassigned && !isNever(leftType) {
+		// narrowing only applies to reads, the new value may be anything of the declared type
+		leftType = declaredType
+	}
+	rightType := parser.getAssignedExpressionType(assignToken, assignmentExpression.Expression, leftType, context)
+	context.MarkAssigned(name)
+	if reassigned {
+		context.ClearNarrowing(name)
+	}
 	if isNever(leftType) || isNever(rightType) {
 		return &types.Never{}
 	}
 
+	if context.IsConstant(name) {
+		parser.error(assignmentExpression.IdentToken, "Cannot assign to constant '%s'", name)
+		return &types.Never{}
+	}
+
+	return parser.getAssignedType(assignToken, leftType, rightType, context)
+}
+
+// getAssignedExpressionType types the right side of an assignment, which is expected to have the type of the left
+// side unless a compound operator is used
+func (parser *Parser) getAssignedExpressionType(assignToken *token.Token, expression Expression, leftType types.Type, context *types.Context) types.Type {
+	switch assignToken.Type {
+	case token.Assign, token.NullishAssign:
+		return parser.getExpectedExpressionType(expression, leftType, context)
+	default:
+		return parser.getExpressionType(expression, context)
+	}
+}
+
+func (parser *Parser) getAssignedType(assignToken *token.Token, leftType types.Type, rightType types.Type, context *types.Context) types.Type {
+	switch assignToken.Type {
+	case token.Assign:
+	case token.NullishAssign:
+		optionalType, isOptional := leftType.(*types.Optional)
+		if !isOptional {
+			parser.error(assignToken, "Type '%s' is not optional", leftType.ToString())
+			return &types.Never{}
+		}
+		if optionalType.Base.IsAssignable(rightType, context) {
+			return optionalType.Base
+		}
+	default:
+		rightType = parser.getInfixOperatorType(assignToken, token.CompoundAssignments[assignToken.Type], leftType, rightType)
+		if isNever(rightType) {
+			return &types.Never{}
+		}
+	}
+
 	if !leftType.IsAssignable(rightType, context) {
-		parser.error(assignmentExpression.AssignToken, "Type '%s' is not assignable to '%s'",
-			rightType.ToString(), leftType.ToString())
+		parser.error(assignToken, "Type '%s' is not assignable to '%s'", rightType.ToString(), leftType.ToString())
 		return &types.Never{}
 	}
 	return rightType
@@ -134,25 +391,44 @@ func (parser *Parser) getAssignmentExpressionType(assignmentExpression *Assignme
 
 func (parser *Parser) getCallExpressionType(callExpression *CallExpression, context *types.Context) types.Type {
 	functionType := parser.getExpressionType(callExpression.Function, context)
+	member, isMember := callExpression.Function.(*MemberAccessExpression)
+	optionalCall := isMember && member.Optional && !isNever(functionType)
+	if optionalCall {
+		functionType = member.MemberType
+	}
 
 	switch functionType := functionType.(type) {
 	case *types.Never:
 		return &types.Never{}
 	case *types.Function:
-		if len(functionType.ParameterTypes) == len(callExpression.Arguments) {
-			for i, parameterType := range functionType.ParameterTypes {
-				if isNever(parameterType) {
+		if arguments := parser.resolveArguments(callExpression, functionType); arguments != nil {
+			argumentTypes := make([]types.Type, len(arguments))
+			for i, argument := range arguments {
+				if argument == nil {
+					continue
+				}
+				if len(functionType.TypeParameters) == 0 {
+					argumentTypes[i] = parser.getExpectedExpressionType(argument, functionType.ParameterType(i), context)
+				} else {
+					argumentTypes[i] = parser.getExpressionType(argument, context)
+				}
+			}
+			functionType = parser.inferTypeArguments(callExpression, functionType, argumentTypes, context)
+			for i, argumentType := range argumentTypes {
+				parameterType := functionType.ParameterType(i)
+				if isNever(parameterType) || arguments[i] == nil {
 					continue
 				}
-				argumentType := parser.getExpressionType(callExpression.Arguments[i], context)
 				if !isNever(argumentType) && !parameterType.IsAssignable(argumentType, context) {
-					parser.error(callExpression.Arguments[i].Token(), "Type '%s' is not assignable to '%s'",
+					parser.error(arguments[i].Token(), "Type '%s' is not assignable to '%s'",
 						argumentType.ToString(), parameterType.ToString())
 				}
 			}
-		} else {
-			parser.error(callExpression.ParenToken, "Mismatching amount of arguments (%d vs %d)",
-				len(callExpression.Arguments), len(functionType.ParameterTypes))
+		}
+		// the called function may reassign variables it can see
+		context.ClearReassignableNarrowing()
+		if optionalCall {
+			return types.NewOptional(functionType.ReturnType)
 		}
 		return functionType.ReturnType
 	default:
@@ -161,8 +437,152 @@ func (parser *Parser) getCallExpressionType(callExpression *CallExpression, cont
 	}
 }
 
+// resolveArguments orders the arguments by parameter, leaving nil where a default value is used. Arguments of a
+// variadic parameter are appended after the fixed ones.
+func (parser *Parser) resolveArguments(callExpression *CallExpression, functionType *types.Function) []Expression {
+
+	arguments := make([]Expression, functionType.FixedCount())
+	indices := make([]int, len(callExpression.Arguments))
+	named := false
+
+	for i, argument := range callExpression.Arguments {
+		index := i
+		if callExpression.ArgumentNames != nil && callExpression.ArgumentNames[i] != nil {
+			name := callExpression.ArgumentNames[i]
+			named = true
+			index = -1
+			for j, parameterName := range functionType.ParameterNames {
+				if parameterName == name.Value {
+					index = j
+				}
+			}
+			if index < 0 {
+				parser.error(name.IdentToken, "Unknown parameter '%s'", name.Value)
+				return nil
+			}
+			if index >= len(arguments) {
+				parser.error(name.IdentToken, "Variadic parameter '%s' cannot be passed by name", name.Value)
+				return nil
+			}
+			if arguments[index] != nil {
+				parser.error(name.IdentToken, "Duplicate argument for parameter '%s'", name.Value)
+				return nil
+			}
+		} else if named {
+			parser.error(argument.Token(), "Positional argument after named argument")
+			return nil
+		} else if i >= len(arguments) {
+			if !functionType.Variadic {
+				break
+			}
+			arguments = append(arguments, argument)
+			indices[i] = index
+			continue
+		}
+		arguments[index] = argument
+		indices[i] = index
+	}
+
+	required := functionType.RequiredCount()
+	for i := 0; i < required; i++ {
+		if arguments[i] == nil && named {
+			parser.error(callExpression.ParenToken, "Missing argument for parameter '%s'", functionType.ParameterNames[i])
+			return nil
+		}
+	}
+	if len(callExpression.Arguments) > len(arguments) || !named && len(callExpression.Arguments) < required {
+		parser.error(callExpression.ParenToken, "Mismatching amount of arguments (%d vs %d)",
+			len(callExpression.Arguments), len(functionType.ParameterTypes))
+		return nil
+	}
+
+	if named {
+		callExpression.ParameterIndices = indices
+	}
+	return arguments
+}
+
+func (parser *Parser) inferTypeArguments(callExpression *CallExpression, functionType *types.Function, argumentTypes []types.Type, context *types.Context) *types.Function {
+	if len(functionType.TypeParameters) == 0 {
+		return functionType
+	}
+	bindings := make(types.Bindings)
+	for i, argumentType := range argumentTypes {
+		if argumentType != nil {
+			types.Unify(functionType.ParameterType(i), argumentType, bindings, context)
+		}
+	}
+
+	for _, typeParameter := range functionType.TypeParameters {
+		if _, bound := bindings[typeParameter]; bound {
+			continue
+		}
+		if !containsNever(argumentTypes) {
+			if actualType := inferUnconstrained(typeParameter, functionType, argumentTypes, context); actualType != nil {
+				parser.error(callExpression.ParenToken, "Type '%s' does not satisfy constraint '%s' of '%s'",
+					actualType.ToString(), types.Substitute(typeParameter.Constraint, bindings).ToString(),
+					typeParameter.Name)
+			} else if typeParameter.Constraint != nil {
+				parser.error(callExpression.ParenToken, "Cannot infer type parameter '%s' satisfying '%s'",
+					typeParameter.Name, typeParameter.Constraint.ToString())
+			} else {
+				parser.error(callExpression.ParenToken, "Cannot infer type parameter '%s'", typeParameter.Name)
+			}
+		}
+		bindings[typeParameter] = &types.Never{}
+	}
+	return types.Substitute(functionType, bindings).(*types.Function)
+}
+
+// inferUnconstrained infers a constrained type parameter while ignoring its constraint, returning nil if the
+// arguments do not determine it
+func inferUnconstrained(typeParameter *types.TypeParameter, functionType *types.Function, argumentTypes []types.Type, context *types.Context) types.Type {
+	if typeParameter.Constraint == nil {
+		return nil
+	}
+	unconstrained := &types.TypeParameter{Name: typeParameter.Name}
+	replacement := types.Bindings{typeParameter: unconstrained}
+	bindings := make(types.Bindings)
+	for i, argumentType := range argumentTypes {
+		if argumentType != nil {
+			types.Unify(types.Substitute(functionType.ParameterType(i), replacement), argumentType, bindings, context)
+		}
+	}
+	return bindings[unconstrained]
+}
+
+func containsNever(theTypes []types.Type) bool {
+	for _, theType := range theTypes {
+		if isNever(theType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (parser *Parser) getIterableElementType(iterable Expression, context *types.Context) types.Type {
+	iterableType := parser.getExpressionType(iterable, context)
+	switch iterableType := iterableType.(type) {
+	case *types.Never:
+		return iterableType
+	case *types.String:
+		return &types.String{}
+	case *types.Array:
+		return iterableType.ElementType
+	case *types.Map:
+		return iterableType.KeyType
+	default:
+		parser.error(iterable.Token(), "Cannot iterate over '%s'", iterableType.ToString())
+		return &types.Never{}
+	}
+}
+
 func (parser *Parser) getIncrementExpressionType(incrementExpression *IncrementExpression, context *types.Context) types.Type {
 	identType := parser.getExpressionType(incrementExpression.Name, context)
+	if context.IsConstant(incrementExpression.Name.Value) {
+		parser.error(incrementExpression.Name.IdentToken, "Cannot assign to constant '%s'", incrementExpression.Name.Value)
+		return &types.Never{}
+	}
 	switch identType.(type) {
 	case *types.Never, *types.Int, *types.Float:
 		return identType
@@ -177,9 +597,370 @@ func (parser *Parser) getMemberAccessExpressionType(memberAccessExpression *Memb
 	if isNever(memberAccessExpression.ParentType) {
 		return memberAccessExpression.ParentType
 	}
+	if memberAccessExpression.Optional {
+		return types.NewOptional(memberAccessExpression.MemberType)
+	}
 	return memberAccessExpression.MemberType
 }
 
+func (parser *Parser) getIndexExpressionType(indexExpression *IndexExpression, context *types.Context) types.Type {
+	leftType, indexType := parser.getExpressionType(indexExpression.Expression, context), parser.getExpressionType(indexExpression.Index, context)
+	if isNever(leftType) || isNever(indexType) {
+		return &types.Never{}
+	}
+
+	switch leftType := leftType.(type) {
+	case *types.Array:
+		if _, isInt := indexType.(*types.Int); !isInt {
+			parser.error(indexExpression.Index.Token(), "Type '%s' is not assignable to '%s'", indexType.ToString(),
+				types.TypeInt)
+			return &types.Never{}
+		}
+		return leftType.ElementType
+	case *types.Map:
+		if !leftType.KeyType.IsAssignable(indexType, context) {
+			parser.error(indexExpression.Index.Token(), "Type '%s' is not assignable to '%s'", indexType.ToString(),
+				leftType.KeyType.ToString())
+			return &types.Never{}
+		}
+		return leftType.ValueType
+	case *types.Tuple:
+		literal, isLiteral := indexExpression.Index.(*IntegerLiteral)
+		if !isLiteral {
+			parser.error(indexExpression.Index.Token(), "Tuple index must be an integer literal")
+			return &types.Never{}
+		}
+		if literal.Value < 0 || literal.Value >= int64(len(leftType.ElementTypes)) {
+			parser.error(indexExpression.Index.Token(), "Index %d out of bounds for '%s'", literal.Value,
+				leftType.ToString())
+			return &types.Never{}
+		}
+		return leftType.ElementTypes[literal.Value]
+	}
+
+	parser.error(indexExpression.LBracketToken, "Cannot index '%s'", leftType.ToString())
+	return &types.Never{}
+}
+
+func (parser *Parser) getIndexAssignmentExpressionType(indexAssignmentExpression *IndexAssignmentExpression, context *types.Context) types.Type {
+	leftType := parser.getExpressionType(indexAssignmentExpression.Index, context)
+	rightType := parser.getAssignedExpressionType(indexAssignmentExpression.AssignToken, indexAssignmentExpression.Expression, leftType, context)
+	if isNever(leftType) || isNever(rightType) {
+		return &types.Never{}
+	}
+
+	if _, isTuple := parser.getExpressionType(indexAssignmentExpression.Index.Expression, context).(*types.Tuple); isTuple {
+		parser.error(indexAssignmentExpression.AssignToken, "Cannot assign to tuple element")
+		return &types.Never{}
+	}
+
+	return parser.getAssignedType(indexAssignmentExpression.AssignToken, leftType, rightType, context)
+}
+
+func (parser *Parser) getMemberAssignmentExpressionType(memberAssignmentExpression *MemberAssignmentExpression, context *types.Context) types.Type {
+	member := memberAssignmentExpression.Member
+	leftType := parser.getExpressionType(member, context)
+	rightType := parser.getAssignedExpressionType(memberAssignmentExpression.AssignToken, memberAssignmentExpression.Expression, leftType, context)
+	if isNever(leftType) || isNever(rightType) {
+		return &types.Never{}
+	}
+
+	if structType, isStruct := member.ParentType.(*types.Struct); !isStruct || structType.Fields[member.Member.Value] == nil {
+		parser.error(member.DotToken, "Cannot assign to member '%s'", member.Member.Value)
+		return &types.Never{}
+	}
+
+	return parser.getAssignedType(memberAssignmentExpression.AssignToken, leftType, rightType, context)
+}
+
+func (parser *Parser) getStructLiteralType(structLiteral *StructLiteral, context *types.Context) types.Type {
+	structType := structLiteral.Type
+	initialized := make(map[string]bool)
+
+	for i, fieldName := range structLiteral.FieldNames {
+		valueType := parser.getExpressionType(structLiteral.Values[i], context)
+		fieldType, exists := structType.Fields[fieldName.Value]
+		if !exists {
+			parser.error(fieldName.IdentToken, "Field '%s' does not exist on '%s'", fieldName.Value,
+				structLiteral.TypeName.Value)
+			continue
+		}
+		if initialized[fieldName.Value] {
+			parser.error(fieldName.IdentToken, "Duplicate field '%s'", fieldName.Value)
+			continue
+		}
+		initialized[fieldName.Value] = true
+		if !isNever(valueType) && !isNever(fieldType) && !fieldType.IsAssignable(valueType, context) {
+			parser.error(structLiteral.Values[i].Token(), "Type '%s' is not assignable to '%s'", valueType.ToString(),
+				fieldType.ToString())
+		}
+	}
+
+	for _, name := range structType.FieldNames {
+		if !initialized[name] && !structType.Fields[name].IsAssignable(&types.Null{}, context) {
+			parser.error(structLiteral.TypeName.IdentToken, "Missing field '%s'", name)
+		}
+	}
+
+	return structType
+}
+
+func (parser *Parser) getEnumLiteralType(enumLiteral *EnumLiteral, context *types.Context) types.Type {
+	payloadTypes := enumLiteral.Type.Variants[enumLiteral.Variant.Value]
+	if len(enumLiteral.Arguments) != len(payloadTypes) {
+		parser.error(enumLiteral.Variant.IdentToken, "Mismatching amount of values (%d vs %d)",
+			len(enumLiteral.Arguments), len(payloadTypes))
+		return &types.Never{}
+	}
+
+	for i, argument := range enumLiteral.Arguments {
+		argumentType := parser.getExpressionType(argument, context)
+		if !isNever(argumentType) && !isNever(payloadTypes[i]) && !payloadTypes[i].IsAssignable(argumentType, context) {
+			parser.error(argument.Token(), "Type '%s' is not assignable to '%s'", argumentType.ToString(),
+				payloadTypes[i].ToString())
+		}
+	}
+
+	return enumLiteral.Type
+}
+
+func (parser *Parser) getTupleLiteralType(tupleLiteral *TupleLiteral, context *types.Context) types.Type {
+	elementTypes := make([]types.Type, len(tupleLiteral.Elements))
+	for i, element := range tupleLiteral.Elements {
+		elementTypes[i] = parser.getExpressionType(element, context)
+		if isNever(elementTypes[i]) {
+			return &types.Never{}
+		}
+		if parser.isUninferred(elementTypes[i]) {
+			parser.error(element.Token(), "Cannot infer element type of empty array or map")
+			return &types.Never{}
+		}
+	}
+	return &types.Tuple{ElementTypes: elementTypes}
+}
+
+func (parser *Parser) getInterpolatedStringLiteralType(literal *InterpolatedStringLiteral, context *types.Context) types.Type {
+	for _, part := range literal.Parts {
+		partType := parser.getExpressionType(part, context)
+		if isNever(partType) {
+			return &types.Never{}
+		}
+		if _, isVoid := partType.(*types.Void); isVoid {
+			parser.error(part.Token(), "Cannot interpolate value of type 'void'")
+			return &types.Never{}
+		}
+	}
+	return &types.String{}
+}
+
+func (parser *Parser) getArrayLiteralType(literal *ArrayLiteral, expectedType *types.Array, context *types.Context) *types.Array {
+	if expectedType == nil {
+		arrayType := &types.Array{ElementType: parser.getCommonType(literal.Elements, context)}
+		if len(literal.Elements) == 0 {
+			parser.uninferredTypes[arrayType] = true
+		} else if parser.isUninferred(arrayType.ElementType) {
+			parser.error(literal.LBracketToken, "Cannot infer element type of empty array or map")
+			arrayType.ElementType = &types.Never{}
+		}
+		return arrayType
+	}
+	parser.checkExpectedTypes(literal.Elements, expectedType.ElementType, context)
+	return &types.Array{ElementType: expectedType.ElementType}
+}
+
+func (parser *Parser) getMapLiteralType(literal *MapLiteral, expectedType *types.Map, context *types.Context) *types.Map {
+	if expectedType != nil {
+		parser.checkExpectedTypes(literal.Keys, expectedType.KeyType, context)
+		parser.checkExpectedTypes(literal.Values, expectedType.ValueType, context)
+		return &types.Map{KeyType: expectedType.KeyType, ValueType: expectedType.ValueType}
+	}
+	keyType, valueType := parser.getCommonType(literal.Keys, context), parser.getCommonType(literal.Values, context)
+	if !types.IsHashable(keyType) {
+		parser.error(literal.Keys[0].Token(), "Type '%s' cannot be used as map key", keyType.ToString())
+		keyType = &types.Never{}
+	}
+	mapType := &types.Map{KeyType: keyType, ValueType: valueType}
+	if len(literal.Keys) == 0 {
+		parser.uninferredTypes[mapType] = true
+	} else if parser.isUninferred(valueType) {
+		parser.error(literal.LBraceToken, "Cannot infer element type of empty array or map")
+		mapType.ValueType = &types.Never{}
+	}
+	return mapType
+}
+
+// isUninferred reports whether a type contains the type of an empty literal that was left to inference
+func (parser *Parser) isUninferred(theType types.Type) bool {
+	if parser.uninferredTypes[theType] {
+		return true
+	}
+	switch theType := theType.(type) {
+	case *types.Array:
+		return parser.isUninferred(theType.ElementType)
+	case *types.Map:
+		return parser.isUninferred(theType.KeyType) || parser.isUninferred(theType.ValueType)
+	case *types.Tuple:
+		for _, elementType := range theType.ElementTypes {
+			if parser.isUninferred(elementType) {
+				return true
+			}
+		}
+	case *types.Optional:
+		return parser.isUninferred(theType.Base)
+	case *types.Union:
+		for _, member := range theType.Types {
+			if parser.isUninferred(member) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (parser *Parser) checkExpectedTypes(expressions []Expression, expectedType types.Type, context *types.Context) {
+	for _, expression := range expressions {
+		actualType := parser.getExpectedExpressionType(expression, expectedType, context)
+		if !isNever(actualType) && !isNever(expectedType) && !expectedType.IsAssignable(actualType, context) {
+			parser.error(expression.Token(), "Type '%s' is not assignable to '%s'", actualType.ToString(),
+				expectedType.ToString())
+		}
+	}
+}
+
+// expectedArrayType finds the array type within an expected type that an array literal should have
+func expectedArrayType(expectedType types.Type) *types.Array {
+	switch expectedType := expectedType.(type) {
+	case *types.Array:
+		return expectedType
+	case *types.Optional:
+		return expectedArrayType(expectedType.Base)
+	case *types.Union:
+		var found *types.Array
+		for _, member := range expectedType.Types {
+			if arrayType, isArray := member.(*types.Array); isArray {
+				if found != nil {
+					return nil // ambiguous
+				}
+				found = arrayType
+			}
+		}
+		return found
+	}
+	return nil
+}
+
+// expectedMapType finds the map type within an expected type that a map literal should have
+func expectedMapType(expectedType types.Type) *types.Map {
+	switch expectedType := expectedType.(type) {
+	case *types.Map:
+		return expectedType
+	case *types.Optional:
+		return expectedMapType(expectedType.Base)
+	case *types.Union:
+		var found *types.Map
+		for _, member := range expectedType.Types {
+			if mapType, isMap := member.(*types.Map); isMap {
+				if found != nil {
+					return nil // ambiguous
+				}
+				found = mapType
+			}
+		}
+		return found
+	}
+	return nil
+}
+
+func (parser *Parser) getCommonType(expressions []Expression, context *types.Context) types.Type {
+	var commonType types.Type = &types.Never{}
+	for i, expression := range expressions {
+		currentType := parser.getExpressionType(expression, context)
+		if i == 0 {
+			commonType = currentType
+		} else if supertype, ok := types.CommonSupertype(commonType, currentType, context); ok {
+			commonType = supertype
+		} else {
+			parser.error(expression.Token(), "Type '%s' is not assignable to '%s'", currentType.ToString(),
+				commonType.ToString())
+		}
+	}
+	return commonType
+}
+
+func getNarrowedTypes(condition Expression, context *types.Context, truthy bool) map[string]types.Type {
+	narrowedTypes := make(map[string]types.Type)
+
+	switch condition := condition.(type) {
+	case *IsExpression:
+		if identifier, isIdentifier := condition.Expression.(*Identifier); isIdentifier {
+			if declaredType, ok := context.GetMemberType(identifier.Value); ok {
+				if truthy {
+					narrowedTypes[identifier.Value] = types.Narrow(declaredType, condition.Type, context)
+				} else {
+					narrowedTypes[identifier.Value] = types.Exclude(declaredType, condition.Type, context)
+				}
+			}
+		}
+	case *PrefixExpression:
+		if condition.Operator == token.Bang {
+			return getNarrowedTypes(condition.Expression, context, !truthy)
+		}
+	case *InfixExpression:
+		switch {
+		case condition.Operator == token.LogicalAnd && truthy || condition.Operator == token.LogicalOr && !truthy:
+			narrowedTypes = getNarrowedTypes(condition.Left, context, truthy)
+			rightContext := extendNarrowedContext(condition.Left, context, truthy)
+			for name, narrowedType := range getNarrowedTypes(condition.Right, rightContext, truthy) {
+				narrowedTypes[name] = narrowedType
+			}
+		case condition.Operator == token.NEQ && truthy || condition.Operator == token.EQ && !truthy:
+			if identifier := getNullComparedIdentifier(condition); identifier != nil {
+				if declaredType, ok := context.GetMemberType(identifier.Value); ok {
+					narrowedTypes[identifier.Value] = types.Exclude(declaredType, &types.Null{}, context)
+				}
+			}
+		}
+	}
+
+	return narrowedTypes
+}
+
+func getRightContext(infixExpression *InfixExpression, context *types.Context) *types.Context {
+	switch infixExpression.Operator {
+	case token.LogicalAnd:
+		return extendNarrowedContext(infixExpression.Left, context, true)
+	case token.LogicalOr:
+		return extendNarrowedContext(infixExpression.Left, context, false)
+	default:
+		return context
+	}
+}
+
+func getNullComparedIdentifier(condition *InfixExpression) *Identifier {
+	left, leftIsIdentifier := condition.Left.(*Identifier)
+	right, rightIsIdentifier := condition.Right.(*Identifier)
+	_, leftIsNull := condition.Left.(*NullLiteral)
+	_, rightIsNull := condition.Right.(*NullLiteral)
+	if leftIsIdentifier && rightIsNull {
+		return left
+	} else if rightIsIdentifier && leftIsNull {
+		return right
+	}
+	return nil
+}
+
+func extendNarrowedContext(condition Expression, context *types.Context, truthy bool) *types.Context {
+	narrowedContext := types.ExtendContext(context)
+	narrowContext(narrowedContext, getNarrowedTypes(condition, context, truthy))
+	return narrowedContext
+}
+
+func narrowContext(narrowedContext *types.Context, narrowedTypes map[string]types.Type) {
+	for name, narrowedType := range narrowedTypes {
+		narrowedContext.NarrowMemberType(name, narrowedType)
+	}
+}
+
 func isNever(theType types.Type) bool {
 	_, isNever := theType.(*types.Never)
 	return isNever
@@ -9,16 +9,19 @@ type TypePrecedence int
 
 const (
 	TypeLowest TypePrecedence = iota
+	TypeUnion
+	TypeIntersection
 	TypeOptional
+	TypeElement
 )
 
 var typePrecedences = map[token.Type]TypePrecedence{
-	token.Qmark: TypeOptional,
+	token.Pipe:    TypeUnion,
+	token.Amp:     TypeIntersection,
+	token.Qmark:   TypeOptional,
+	token.Nullish: TypeOptional,
 }
 
-var prefixTypeParseFunctions = make(map[token.Type]func(*types.Context) types.Type)
-var infixTypeParseFunctions = make(map[token.Type]func(*types.Context, types.Type) types.Type)
-
 func getTypePrecedence(token *token.Token) TypePrecedence {
 	if precedence, exists := typePrecedences[token.Type]; exists {
 		return precedence
@@ -27,18 +30,25 @@ func getTypePrecedence(token *token.Token) TypePrecedence {
 }
 
 func (parser *Parser) registerTypeParseFunctions() {
-	prefixTypeParseFunctions[token.Ident] = parser.parseTypeLiteral
-	prefixTypeParseFunctions[token.Null] = parser.parseTypeLiteral
-	prefixTypeParseFunctions[token.Void] = parser.parseTypeLiteral
-	prefixTypeParseFunctions[token.Func] = parser.parseFunctionTypeLiteral
-	prefixTypeParseFunctions[token.Iface] = parser.parseIfaceTypeLiteral
+	parser.prefixTypeParseFunctions[token.Ident] = parser.parseTypeLiteral
+	parser.prefixTypeParseFunctions[token.Null] = parser.parseTypeLiteral
+	parser.prefixTypeParseFunctions[token.Void] = parser.parseTypeLiteral
+	parser.prefixTypeParseFunctions[token.Func] = parser.parseFunctionTypeLiteral
+	parser.prefixTypeParseFunctions[token.Iface] = parser.parseIfaceTypeLiteral
+	parser.prefixTypeParseFunctions[token.Struct] = parser.parseStructTypeLiteral
+	parser.prefixTypeParseFunctions[token.Enum] = parser.parseEnumTypeLiteral
+	parser.prefixTypeParseFunctions[token.LBracket] = parser.parseArrayTypeLiteral
+	parser.prefixTypeParseFunctions[token.LParen] = parser.parseGroupedTypeLiteral
 
-	infixTypeParseFunctions[token.Qmark] = parser.parseOptionalTypeLiteral
+	parser.infixTypeParseFunctions[token.Qmark] = parser.parseOptionalTypeLiteral
+	parser.infixTypeParseFunctions[token.Nullish] = parser.parseOptionalTypeLiteral
+	parser.infixTypeParseFunctions[token.Pipe] = parser.parseUnionTypeLiteral
+	parser.infixTypeParseFunctions[token.Amp] = parser.parseIntersectionTypeLiteral
 }
 
 func (parser *Parser) parseType(context *types.Context, precedence TypePrecedence) types.Type {
 	currentToken := parser.current()
-	prefixFunction := prefixTypeParseFunctions[currentToken.Type]
+	prefixFunction := parser.prefixTypeParseFunctions[currentToken.Type]
 	if prefixFunction == nil {
 		if currentToken.Type != token.Illegal {
 			parser.error(currentToken, "Unexpected %s", currentToken.ToString())
@@ -49,7 +59,7 @@ func (parser *Parser) parseType(context *types.Context, precedence TypePrecedenc
 	theType := prefixFunction(context)
 
 	for parser.peek().Type != token.Semi && precedence < getTypePrecedence(parser.peek()) {
-		infixFunction := infixTypeParseFunctions[parser.peek().Type]
+		infixFunction := parser.infixTypeParseFunctions[parser.peek().Type]
 		if infixFunction == nil {
 			break
 		}
@@ -78,12 +88,17 @@ func (parser *Parser) parseTypeLiteral(context *types.Context) types.Type {
 			return &types.Int{}
 		case types.TypeFloat:
 			return &types.Float{}
+		case types.TypeMap:
+			return parser.parseMapTypeLiteral(context)
 		default:
 			theType, ok := context.GetType(typeName)
 			if !ok {
 				parser.error(currentToken, "Unknown type '%s'", typeName)
 				return &types.Never{}
 			}
+			if generic, isGeneric := theType.(*types.Generic); isGeneric {
+				return parser.parseGenericTypeInstance(context, generic)
+			}
 			return theType
 		}
 	case token.Null:
@@ -99,9 +114,17 @@ func (parser *Parser) parseFunctionTypeLiteral(context *types.Context) types.Typ
 		return &types.Never{}
 	}
 	parameterTypes := make([]types.Type, 0)
+	variadic := false
 	if parser.peek().Type != token.RParen {
 		for {
 			parser.consume()
+			if variadic {
+				parser.error(parser.current(), "Variadic parameter must be the last parameter")
+			}
+			if parser.current().Type == token.Ellipsis {
+				variadic = true
+				parser.consume()
+			}
 			parameterType := parser.parseType(context, TypeLowest)
 			parameterTypes = append(parameterTypes, parameterType)
 			if parser.peek().Type == token.Comma {
@@ -118,6 +141,7 @@ func (parser *Parser) parseFunctionTypeLiteral(context *types.Context) types.Typ
 	returnType := parser.parseType(context, TypeLowest)
 	return &types.Function{
 		ParameterTypes: parameterTypes,
+		Variadic:       variadic,
 		ReturnType:     returnType,
 	}
 }
@@ -144,13 +168,201 @@ func (parser *Parser) parseIfaceTypeLiteral(context *types.Context) types.Type {
 	return iface
 }
 
+func (parser *Parser) parseStructTypeLiteral(context *types.Context) types.Type {
+
+	if !parser.assertNext(token.LBrace) {
+		return &types.Never{}
+	}
+
+	structType := &types.Struct{FieldNames: make([]string, 0), Fields: make(map[string]types.Type)}
+	for parser.peek().Type == token.Ident {
+		parser.consume()
+		nameToken := parser.current()
+		name := nameToken.Literal
+		parser.assertNext(token.Colon)
+		parser.consume()
+		fieldType := parser.parseType(context, TypeLowest)
+		if _, exists := structType.Fields[name]; exists {
+			parser.error(nameToken, "Duplicate field '%s'", name)
+		} else {
+			structType.FieldNames = append(structType.FieldNames, name)
+			structType.Fields[name] = fieldType
+		}
+		parser.assertNext(token.Semi)
+	}
+
+	parser.assertNext(token.RBrace)
+
+	return structType
+}
+
+func (parser *Parser) parseEnumTypeLiteral(context *types.Context) types.Type {
+
+	if !parser.assertNext(token.LBrace) {
+		return &types.Never{}
+	}
+
+	enumType := &types.Enum{VariantNames: make([]string, 0), Variants: make(map[string][]types.Type)}
+	for parser.peek().Type == token.Ident {
+		parser.consume()
+		nameToken := parser.current()
+		name := nameToken.Literal
+		payloadTypes := make([]types.Type, 0)
+		if parser.peek().Type == token.LParen {
+			parser.consume()
+			for {
+				parser.consume()
+				payloadTypes = append(payloadTypes, parser.parseType(context, TypeLowest))
+				if parser.peek().Type != token.Comma {
+					break
+				}
+				parser.consume()
+			}
+			parser.assertNext(token.RParen)
+		}
+		if _, exists := enumType.Variants[name]; exists {
+			parser.error(nameToken, "Duplicate variant '%s'", name)
+		} else {
+			enumType.VariantNames = append(enumType.VariantNames, name)
+			enumType.Variants[name] = payloadTypes
+		}
+		parser.assertNext(token.Semi)
+	}
+
+	parser.assertNext(token.RBrace)
+
+	return enumType
+}
+
+func (parser *Parser) parseArrayTypeLiteral(context *types.Context) types.Type {
+	if !parser.assertNext(token.RBracket) {
+		return &types.Never{}
+	}
+	parser.consume()
+	return &types.Array{ElementType: parser.parseType(context, TypeElement)}
+}
+
+func (parser *Parser) parseGroupedTypeLiteral(context *types.Context) types.Type {
+	parser.consume()
+	theType := parser.parseType(context, TypeLowest)
+	if parser.peek().Type == token.Comma {
+		tuple := &types.Tuple{ElementTypes: []types.Type{theType}}
+		for parser.peek().Type == token.Comma {
+			parser.consume()
+			parser.consume()
+			tuple.ElementTypes = append(tuple.ElementTypes, parser.parseType(context, TypeLowest))
+		}
+		theType = tuple
+	}
+	if !parser.assertNext(token.RParen) {
+		return &types.Never{}
+	}
+	return theType
+}
+
+func (parser *Parser) parseMapTypeLiteral(context *types.Context) types.Type {
+	if !parser.assertNext(token.LBracket) {
+		return &types.Never{}
+	}
+	keyToken := parser.consume()
+	keyType := parser.parseType(context, TypeLowest)
+	if !parser.assertNext(token.RBracket) {
+		return &types.Never{}
+	}
+	parser.consume()
+	valueType := parser.parseType(context, TypeElement)
+
+	if !types.IsHashable(keyType) {
+		parser.error(keyToken, "Type '%s' cannot be used as map key", keyType.ToString())
+		return &types.Never{}
+	}
+	return &types.Map{KeyType: keyType, ValueType: valueType}
+}
+
+func (parser *Parser) parseGenericTypeInstance(context *types.Context, generic *types.Generic) types.Type {
+	nameToken := parser.current()
+	if parser.peek().Type != token.LT {
+		parser.error(nameToken, "Missing type arguments for '%s'", generic.ToString())
+		return &types.Never{}
+	}
+	parser.consume()
+
+	argumentTokens := make([]*token.Token, 0)
+	typeArguments := make([]types.Type, 0)
+	for {
+		argumentTokens = append(argumentTokens, parser.consume())
+		typeArguments = append(typeArguments, parser.parseType(context, TypeLowest))
+		if parser.peek().Type == token.Comma {
+			parser.consume()
+		} else {
+			break
+		}
+	}
+	if !parser.assertNextGT() {
+		return &types.Never{}
+	}
+
+	if len(typeArguments) != len(generic.TypeParameters) {
+		parser.error(nameToken, "Mismatching amount of type arguments (%d vs %d)", len(typeArguments),
+			len(generic.TypeParameters))
+		return &types.Never{}
+	}
+
+	bindings := make(types.Bindings)
+	for i, typeParameter := range generic.TypeParameters {
+		typeArgument := typeArguments[i]
+		if isNever(typeArgument) {
+			return typeArgument
+		}
+		if typeParameter.Constraint != nil {
+			constraint := types.Substitute(typeParameter.Constraint, bindings)
+			if !constraint.IsAssignable(typeArgument, context) {
+				parser.error(argumentTokens[i], "Type '%s' does not satisfy constraint '%s' of '%s'",
+					typeArgument.ToString(), constraint.ToString(), typeParameter.Name)
+				return &types.Never{}
+			}
+		}
+		bindings[typeParameter] = typeArgument
+	}
+	return types.Substitute(generic.Type, bindings)
+}
+
 /** infix types **/
 
 func (parser *Parser) parseOptionalTypeLiteral(_ *types.Context, left types.Type) types.Type {
-	switch left.(type) {
-	case *types.Never, *types.Null, *types.Void, *types.Optional:
-		return left
-	default:
-		return &types.Optional{Base: left}
+	return types.NewOptional(left)
+}
+
+func (parser *Parser) parseUnionTypeLiteral(context *types.Context, left types.Type) types.Type {
+	parser.consume()
+	right := parser.parseType(context, TypeUnion)
+	if isNever(left) || isNever(right) {
+		return &types.Never{}
+	}
+	return types.NewUnion(left, right)
+}
+
+func (parser *Parser) parseIntersectionTypeLiteral(context *types.Context, left types.Type) types.Type {
+	ampToken := parser.current()
+	parser.consume()
+	right := parser.parseType(context, TypeIntersection)
+	if isNever(left) || isNever(right) {
+		return &types.Never{}
+	}
+
+	leftIface, leftIsIface := left.(*types.Iface)
+	rightIface, rightIsIface := right.(*types.Iface)
+	if !leftIsIface || !rightIsIface {
+		parser.error(ampToken, "Cannot intersect '%s' and '%s'", left.ToString(), right.ToString())
+		return &types.Never{}
+	}
+
+	intersection, conflictingMember, ok := types.Intersect(leftIface, rightIface, context)
+	if !ok {
+		parser.error(ampToken, "Conflicting types '%s' and '%s' for member '%s'",
+			leftIface.Members[conflictingMember].ToString(), rightIface.Members[conflictingMember].ToString(),
+			conflictingMember)
+		return &types.Never{}
 	}
+	return intersection
 }
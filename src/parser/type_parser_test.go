@@ -37,6 +37,46 @@ func TestTypeParser(t *testing.T) {
 		},
 	)
 
+	assertType(t, "[]int", &types.Array{ElementType: &types.Int{}})
+	assertType(t, "[][]string?", &types.Optional{Base: &types.Array{ElementType: &types.Array{ElementType: &types.String{}}}})
+	assertType(t, "[][](string?)", &types.Array{ElementType: &types.Array{ElementType: &types.Optional{Base: &types.String{}}}})
+	assertType(t, "map[string](int?)", &types.Map{KeyType: &types.String{}, ValueType: &types.Optional{Base: &types.Int{}}})
+	assertType(t, "[]int | map[string]int", &types.Union{Types: []types.Type{&types.Array{ElementType: &types.Int{}}, &types.Map{KeyType: &types.String{}, ValueType: &types.Int{}}}})
+	assertType(t, "map[[]int]int", &types.Never{})
+
+	assertType(t, "int | string", &types.Union{Types: []types.Type{&types.Int{}, &types.String{}}})
+	assertType(t, "int | string | int", &types.Union{Types: []types.Type{&types.Int{}, &types.String{}}})
+	assertType(t, "int | null | string?", &types.Optional{Base: &types.Union{Types: []types.Type{&types.Int{}, &types.String{}}}})
+	assertType(t, "([]int) | string", &types.Union{Types: []types.Type{&types.Array{ElementType: &types.Int{}}, &types.String{}}})
+
+	assertType(t,
+		"iface { x: int?; y: int; } & iface { x: int; z: bool; } | null",
+		&types.Optional{Base: &types.Iface{Members: map[string]types.Type{"x": &types.Int{}, "y": &types.Int{}, "z": &types.Bool{}}}},
+	)
+	assertType(t, "iface { x: int; } & iface { x: string; }", &types.Never{})
+	assertType(t, "iface { x: int; } & int", &types.Never{})
+
+	assertType(t,
+		"struct { x: int; y: float?; }",
+		&types.Struct{
+			FieldNames: []string{"x", "y"},
+			Fields:     map[string]types.Type{"x": &types.Int{}, "y": &types.Optional{Base: &types.Float{}}},
+		},
+	)
+
+	assertType(t, "(int, (string)?)", &types.Tuple{ElementTypes: []types.Type{&types.Int{}, &types.Optional{Base: &types.String{}}}})
+
+	assertType(t,
+		"enum { red; ok(int, string?); }",
+		&types.Enum{
+			VariantNames: []string{"red", "ok"},
+			Variants: map[string][]types.Type{
+				"red": {},
+				"ok":  {&types.Int{}, &types.Optional{Base: &types.String{}}},
+			},
+		},
+	)
+
 	assertType(t,
 		"fn string",
 		&types.Never{},
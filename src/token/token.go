@@ -22,6 +22,9 @@ const (
 	IntLiteral
 	FloatLiteral
 	StringLiteral
+	StringHead
+	StringMiddle
+	StringTail
 
 	EQ
 	NEQ
@@ -34,11 +37,26 @@ const (
 	Minus
 	Slash
 	Star
+	Percent
+	Power
+
+	Pipe
+	Caret
+	Tilde
+	ShiftLeft
+	ShiftRight
 
 	LogicalAnd
 	LogicalOr
+	Nullish
 
 	Assign
+	PlusAssign
+	MinusAssign
+	StarAssign
+	SlashAssign
+	PercentAssign
+	NullishAssign
 	Qmark
 	Amp
 	Bang
@@ -46,27 +64,46 @@ const (
 	Decrement
 
 	Dot
+	QmarkDot
+	DotDot
+	Ellipsis
 	Comma
 	Semi
 	Colon
 	DoubleColon
 	Define
+	Arrow
 
 	LParen
 	RParen
 	LBrace
 	RBrace
+	LBracket
+	RBracket
 
 	Func
 	TypeDef
 	Iface
+	Struct
+	Enum
 	Return
 	Let
 	Const
 	If
 	Else
 	For
+	In
 	While
+	Break
+	Continue
+	Throw
+	Try
+	Catch
+	Finally
+	Match
+	Is
+	Import
+	As
 
 	True
 	False
@@ -75,20 +112,41 @@ const (
 )
 
 var Keywords = map[string]Type{
-	"fn":     Func,
-	"return": Return,
-	"let":    Let,
-	"const":  Const,
-	"true":   True,
-	"false":  False,
-	"null":   Null,
-	"void":   Void,
-	"if":     If,
-	"else":   Else,
-	"for":    For,
-	"while":  While,
-	"type":   TypeDef,
-	"iface":  Iface,
+	"fn":       Func,
+	"return":   Return,
+	"let":      Let,
+	"const":    Const,
+	"true":     True,
+	"false":    False,
+	"null":     Null,
+	"void":     Void,
+	"if":       If,
+	"else":     Else,
+	"for":      For,
+	"in":       In,
+	"while":    While,
+	"break":    Break,
+	"continue": Continue,
+	"throw":    Throw,
+	"try":      Try,
+	"catch":    Catch,
+	"finally":  Finally,
+	"match":    Match,
+	"is":       Is,
+	"import":   Import,
+	"as":       As,
+	"type":     TypeDef,
+	"iface":    Iface,
+	"struct":   Struct,
+	"enum":     Enum,
+}
+
+var CompoundAssignments = map[Type]Type{
+	PlusAssign:    Plus,
+	MinusAssign:   Minus,
+	StarAssign:    Star,
+	SlashAssign:   Slash,
+	PercentAssign: Percent,
 }
 
 func (token Token) ToString() string {
@@ -103,6 +161,9 @@ func (tokenType Type) ToString() string {
 		"INT_LITERAL",
 		"FLOAT_LITERAL",
 		"STRING_LITERAL",
+		"STRING_HEAD",
+		"STRING_MIDDLE",
+		"STRING_TAIL",
 		"==",
 		"!=",
 		"<",
@@ -113,34 +174,67 @@ func (tokenType Type) ToString() string {
 		"-",
 		"/",
 		"*",
+		"%",
+		"**",
+		"|",
+		"^",
+		"~",
+		"<<",
+		">>",
 		"&&",
 		"||",
+		"??",
 		"=",
+		"+=",
+		"-=",
+		"*=",
+		"/=",
+		"%=",
+		"??=",
 		"?",
 		"&",
 		"!",
 		"++",
 		"--",
 		".",
+		"?.",
+		"..",
+		"...",
 		",",
 		";",
 		":",
 		"::",
 		":=",
+		"=>",
 		"(",
 		")",
 		"{",
 		"}",
+		"[",
+		"]",
 		"FUNC",
 		"TYPE",
 		"IFACE",
+		"STRUCT",
+		"ENUM",
 		"RETURN",
 		"LET",
 		"CONST",
 		"IF",
 		"ELSE",
 		"FOR",
+		"IN",
 		"WHILE",
+		"BREAK",
+		"CONTINUE",
+		"THROW",
+		"TRY",
+		"CATCH",
+		"FINALLY",
+		"MATCH",
+		"IS",
+		"IMPORT",
+		"AS",
 		"TRUE",
 		"FALSE",
 		"NULL",
@@ -156,6 +250,9 @@ func (tokenType Type) ToStringHumanReadable() string {
 		"integer literal",
 		"float literal",
 		"string literal",
+		"string literal",
+		"string literal",
+		"string literal",
 		"'=='",
 		"'!='",
 		"'<'",
@@ -166,34 +263,67 @@ func (tokenType Type) ToStringHumanReadable() string {
 		"'-'",
 		"'/'",
 		"'*'",
+		"'%'",
+		"'**'",
+		"'|'",
+		"'^'",
+		"'~'",
+		"'<<'",
+		"'>>'",
 		"'&&'",
 		"'||'",
+		"'??'",
 		"'='",
+		"'+='",
+		"'-='",
+		"'*='",
+		"'/='",
+		"'%='",
+		"'??='",
 		"'?'",
 		"'&'",
 		"'!'",
 		"'++'",
 		"'--'",
 		"'.'",
+		"'?.'",
+		"'..'",
+		"'...'",
 		"','",
 		"';'",
 		"':'",
 		"'::'",
 		"':='",
+		"'=>'",
 		"'('",
 		"')'",
 		"'{'",
 		"'}'",
+		"'['",
+		"']'",
 		"'fn'",
 		"'type'",
 		"'iface'",
+		"'struct'",
+		"'enum'",
 		"'return'",
 		"'let'",
 		"'const'",
 		"'if'",
 		"'else'",
 		"'for'",
+		"'in'",
 		"'while'",
+		"'break'",
+		"'continue'",
+		"'throw'",
+		"'try'",
+		"'catch'",
+		"'finally'",
+		"'match'",
+		"'is'",
+		"'import'",
+		"'as'",
 		"'true'",
 		"'false'",
 		"'null'",
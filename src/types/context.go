@@ -8,7 +8,12 @@ type Context struct {
 	parent       *Context
 	typeContexts map[Type]*Context
 	memberStore  map[string]Type
+	constStore   map[string]bool
+	narrowStore  map[string]bool
+	closureStore map[string]bool
 	typeStore    map[string]Type
+	loopLabels   []string
+	function     bool
 	ReturnType   Type
 }
 
@@ -16,6 +21,9 @@ func NewContext() *Context {
 	return &Context{
 		typeContexts: make(map[Type]*Context),
 		memberStore:  make(map[string]Type),
+		constStore:   make(map[string]bool),
+		narrowStore:  make(map[string]bool),
+		closureStore: make(map[string]bool),
 		typeStore:    make(map[string]Type),
 	}
 }
@@ -24,29 +32,53 @@ func ExtendContext(parent *Context) *Context {
 	return &Context{
 		parent:       parent,
 		ReturnType:   parent.ReturnType,
+		loopLabels:   parent.loopLabels,
 		typeContexts: make(map[Type]*Context),
 		memberStore:  make(map[string]Type),
+		constStore:   make(map[string]bool),
+		narrowStore:  make(map[string]bool),
+		closureStore: make(map[string]bool),
 		typeStore:    make(map[string]Type),
 	}
 }
 
+func ExtendFunctionContext(parent *Context, returnType Type) *Context {
+	context := ExtendContext(parent)
+	context.ReturnType = returnType
+	context.loopLabels = nil
+	context.function = true
+	return context
+}
+
+func ExtendLoopContext(parent *Context, label string) *Context {
+	context := ExtendContext(parent)
+	context.loopLabels = append(append([]string{}, parent.loopLabels...), label)
+	return context
+}
+
 func GetMemberTypeContext(context *Context, parentType Type) *Context {
 	newContext := NewContext()
 	currentContext := context
 	for currentContext != nil && parentType != nil {
 		for memberParentType, typeContext := range currentContext.typeContexts {
-			if memberParentType.IsAssignable(parentType, context) {
+			bindings := make(Bindings)
+			if Unify(memberParentType, parentType, bindings, context) {
 				for memberName, memberType := range typeContext.memberStore {
-					newContext.memberStore[memberName] = memberType
+					newContext.memberStore[memberName] = Substitute(memberType, bindings)
 				}
 			}
 		}
 		currentContext = currentContext.parent
 	}
-	if iface, isIface := parentType.(*Iface); isIface {
-		for memberName, memberType := range iface.Members {
+	switch parentType := parentType.(type) {
+	case *Iface:
+		for memberName, memberType := range parentType.Members {
 			newContext.memberStore[memberName] = memberType
 		}
+	case *Struct:
+		for fieldName, fieldType := range parentType.Fields {
+			newContext.memberStore[fieldName] = fieldType
+		}
 	}
 	return newContext
 }
@@ -55,10 +87,61 @@ func CloneContext(context *Context) *Context {
 	return &Context{
 		parent:       context.parent,
 		ReturnType:   context.ReturnType,
+		loopLabels:   context.loopLabels,
 		typeContexts: cloneTypeMap(context.typeContexts),
 		memberStore:  cloneMap(context.memberStore),
+		constStore:   cloneMap(context.constStore),
+		narrowStore:  cloneMap(context.narrowStore),
+		closureStore: cloneMap(context.closureStore),
 		typeStore:    cloneMap(context.typeStore),
+		function:     context.function,
+	}
+}
+
+func (context *Context) Root() *Context {
+	for context.parent != nil {
+		context = context.parent
+	}
+	return context
+}
+
+func (context *Context) MemberTypes() map[string]Type {
+	return cloneMap(context.memberStore)
+}
+
+// ImportMembers defines the members of a module context, it returns the first name that is already defined
+func (context *Context) ImportMembers(module *Context) (string, bool) {
+	for name, memberType := range module.memberStore {
+		if _, ok := context.DefineMemberType(name, memberType); !ok {
+			return name, false
+		}
+		context.constStore[name] = module.constStore[name]
 	}
+	return "", true
+}
+
+// ImportTypes defines the types and type members of a module context, it returns the first name that is already
+// defined differently
+func (context *Context) ImportTypes(module *Context) (string, bool) {
+	for name, theType := range module.typeStore {
+		if existingType, exists := context.GetTypeStrict(name); exists && existingType == theType {
+			continue
+		}
+		if _, ok := context.DefineType(name, theType); !ok {
+			return name, false
+		}
+	}
+	for parentType, typeContext := range module.typeContexts {
+		for name, memberType := range typeContext.memberStore {
+			if existingType, _, exists := context.GetTypeMemberTypeStrict(name, parentType); exists && existingType == memberType {
+				continue
+			}
+			if _, ok := context.DefineTypeMemberType(name, memberType, parentType); !ok {
+				return name, false
+			}
+		}
+	}
+	return "", true
 }
 
 func (context *Context) GetMemberTypeStrict(name string) (Type, bool) {
@@ -82,11 +165,146 @@ func (context *Context) DefineMemberType(name string, memberType Type) (Type, bo
 	return memberType, true
 }
 
+func (context *Context) DefineConstantType(name string, memberType Type) (Type, bool) {
+	definedType, ok := context.DefineMemberType(name, memberType)
+	if ok {
+		context.constStore[name] = true
+	}
+	return definedType, ok
+}
+
+// NarrowMemberType refines the type of a member for reads within this context
+func (context *Context) NarrowMemberType(name string, narrowedType Type) {
+	context.constStore[name] = context.IsConstant(name)
+	context.memberStore[name] = narrowedType
+	context.narrowStore[name] = true
+}
+
+// GetDeclaredMemberType returns the type a member was defined with, ignoring narrowed types
+func (context *Context) GetDeclaredMemberType(name string) (Type, bool) {
+	if memberType, ok := context.memberStore[name]; ok && !context.narrowStore[name] {
+		return memberType, true
+	} else if context.parent != nil {
+		return context.parent.GetDeclaredMemberType(name)
+	}
+	return nil, false
+}
+
+// ClearNarrowing removes the narrowed types of a member up to its definition, e.g. after it has been reassigned
+func (context *Context) ClearNarrowing(name string) {
+	for current := context; current != nil; current = current.parent {
+		if _, ok := current.memberStore[name]; ok {
+			if !current.narrowStore[name] {
+				return
+			}
+			delete(current.memberStore, name)
+			delete(current.constStore, name)
+			delete(current.narrowStore, name)
+		}
+	}
+}
+
+// MarkAssigned records an assignment to a member, so that calls can end its narrowing if the assignment happens
+// within a nested function
+func (context *Context) MarkAssigned(name string) {
+	if declaringContext, outside := context.declaringContext(name); declaringContext != nil && outside {
+		declaringContext.closureStore[name] = true
+	}
+}
+
+// ClearReassignableNarrowing removes the narrowed types of the members a called function may reassign: the ones
+// declared outside the current function and the ones assigned within a nested function
+func (context *Context) ClearReassignableNarrowing() {
+	for current := context; current != nil; current = current.parent {
+		for name := range current.narrowStore {
+			declaringContext, outside := current.declaringContext(name)
+			if current.constStore[name] || declaringContext == nil {
+				continue
+			}
+			if outside || declaringContext.closureStore[name] || !context.isInFunctionOf(current) {
+				current.ClearNarrowing(name)
+			}
+		}
+	}
+}
+
+// declaringContext returns the context that defines a member and whether it lies outside the current function
+func (context *Context) declaringContext(name string) (*Context, bool) {
+	outside := false
+	for current := context; current != nil; current = current.parent {
+		if _, ok := current.memberStore[name]; ok && !current.narrowStore[name] {
+			return current, outside
+		}
+		outside = outside || current.function
+	}
+	return nil, outside
+}
+
+// isInFunctionOf reports whether an enclosing context belongs to the same function as this context
+func (context *Context) isInFunctionOf(enclosing *Context) bool {
+	for current := context; current != enclosing; current = current.parent {
+		if current.function {
+			return false
+		}
+	}
+	return true
+}
+
+func (context *Context) IsConstant(name string) bool {
+	if _, ok := context.GetMemberTypeStrict(name); ok {
+		return context.constStore[name]
+	} else if context.parent != nil {
+		return context.parent.IsConstant(name)
+	}
+	return false
+}
+
+func (context *Context) IsInLoop(label string) bool {
+	if label == "" {
+		return len(context.loopLabels) > 0
+	}
+	for _, loopLabel := range context.loopLabels {
+		if loopLabel == label {
+			return true
+		}
+	}
+	return false
+}
+
 func (context *Context) GetTypeMemberTypeStrict(name string, parentType Type) (Type, Type, bool) {
+	switch parentType := parentType.(type) {
+	case *Iface:
+		if memberType, ok := parentType.Members[name]; ok {
+			return memberType, parentType, true
+		}
+	case *Struct:
+		if fieldType, ok := parentType.Fields[name]; ok {
+			return fieldType, parentType, true
+		}
+	case *Union:
+		var commonType Type = &Never{}
+		for _, member := range parentType.Types {
+			memberType, _, ok := context.GetTypeMemberType(name, member)
+			if !ok {
+				return nil, nil, false
+			}
+			if commonType, ok = CommonSupertype(commonType, memberType, context); !ok {
+				return nil, nil, false
+			}
+		}
+		return commonType, parentType, true
+	case *TypeParameter:
+		if parentType.Constraint != nil {
+			if memberType, resolvedParentType, ok := context.GetTypeMemberTypeStrict(name, parentType.Constraint); ok {
+				return memberType, resolvedParentType, true
+			}
+		}
+	}
 	for resolvedParentType, typeContext := range context.typeContexts {
 		memberType, ok := typeContext.GetMemberTypeStrict(name)
-		if ok && resolvedParentType.IsAssignable(parentType, context) {
-			return memberType, resolvedParentType, true
+		bindings := make(Bindings)
+		if ok && Unify(resolvedParentType, parentType, bindings, context) {
+			return Substitute(memberType, bindings), resolvedParentType, true
 		}
 	}
 	return nil, nil, false
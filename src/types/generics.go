@@ -0,0 +1,221 @@
+package types
+
+type Bindings map[*TypeParameter]Type
+
+type Generic struct {
+	Name           string
+	TypeParameters []*TypeParameter
+	Type           Type
+}
+
+func (generic *Generic) ToString() string {
+	result := generic.Name + "<"
+	for i, typeParameter := range generic.TypeParameters {
+		if i > 0 {
+			result += ", "
+		}
+		result += typeParameter.Name
+	}
+	return result + ">"
+}
+
+func (generic *Generic) IsAssignable(Type, *Context) bool {
+	return false
+}
+
+func Unify(pattern Type, actual Type, bindings Bindings, context *Context) bool {
+	switch pattern := pattern.(type) {
+	case *TypeParameter:
+		if isNever(actual) {
+			return true
+		}
+		if bound, ok := bindings[pattern]; ok {
+			if bound.IsAssignable(actual, context) {
+				return true
+			}
+			supertype, ok := CommonSupertype(bound, actual, context)
+			if !ok || pattern.Constraint != nil && !pattern.Constraint.IsAssignable(supertype, context) {
+				return false
+			}
+			bindings[pattern] = supertype
+			return true
+		}
+		if pattern.Constraint != nil && !pattern.Constraint.IsAssignable(actual, context) {
+			return false
+		}
+		bindings[pattern] = actual
+		return true
+	case *Array:
+		if actual, isArray := actual.(*Array); isArray {
+			return isNever(actual.ElementType) || Unify(pattern.ElementType, actual.ElementType, bindings, context)
+		}
+		return false
+	case *Tuple:
+		actual, isTuple := actual.(*Tuple)
+		if !isTuple || len(pattern.ElementTypes) != len(actual.ElementTypes) {
+			return false
+		}
+		for i := range pattern.ElementTypes {
+			if !Unify(pattern.ElementTypes[i], actual.ElementTypes[i], bindings, context) {
+				return false
+			}
+		}
+		return true
+	case *Map:
+		if actual, isMap := actual.(*Map); isMap {
+			if isNever(actual.KeyType) && isNever(actual.ValueType) {
+				return true
+			}
+			return Unify(pattern.KeyType, actual.KeyType, bindings, context) &&
+				Unify(pattern.ValueType, actual.ValueType, bindings, context)
+		}
+		return false
+	case *Optional:
+		switch actual := actual.(type) {
+		case *Null:
+			return true
+		case *Optional:
+			return Unify(pattern.Base, actual.Base, bindings, context)
+		default:
+			return Unify(pattern.Base, actual, bindings, context)
+		}
+	case *Function:
+		actual, isFunction := actual.(*Function)
+		if !isFunction || !pattern.AcceptsParametersOf(actual) {
+			return false
+		}
+		for i := range pattern.ParameterTypes {
+			// parameters are contravariant, so a wider parameter type of actual is fine as well
+			actualParameterType := actual.ParameterType(i)
+			if !Unify(pattern.ParameterTypes[i], actualParameterType, bindings, context) &&
+				!actualParameterType.IsAssignable(Substitute(pattern.ParameterTypes[i], bindings), context) {
+				return false
+			}
+		}
+		return Unify(pattern.ReturnType, actual.ReturnType, bindings, context)
+	case *Struct:
+		actual, isStruct := actual.(*Struct)
+		if !isStruct || len(pattern.Fields) != len(actual.Fields) {
+			return false
+		}
+		for name, fieldType := range pattern.Fields {
+			actualFieldType, ok := actual.Fields[name]
+			if !ok || !Unify(fieldType, actualFieldType, bindings, context) {
+				return false
+			}
+		}
+		return true
+	case *Enum:
+		actual, isEnum := actual.(*Enum)
+		if !isEnum || pattern.Root() != actual.Root() {
+			return false
+		}
+		for name, payloadTypes := range pattern.Variants {
+			for i, payloadType := range payloadTypes {
+				if !Unify(payloadType, actual.Variants[name][i], bindings, context) {
+					return false
+				}
+			}
+		}
+		return true
+	case *Iface:
+		for name, memberType := range pattern.Members {
+			actualMemberType, _, ok := context.GetTypeMemberType(name, actual)
+			if !ok || !Unify(memberType, actualMemberType, bindings, context) {
+				return false
+			}
+		}
+		return true
+	default:
+		return pattern.IsAssignable(actual, context)
+	}
+}
+
+func Substitute(theType Type, bindings Bindings) Type {
+	if len(bindings) == 0 {
+		return theType
+	}
+	switch theType := theType.(type) {
+	case *TypeParameter:
+		if bound, ok := bindings[theType]; ok {
+			return bound
+		}
+	case *Array:
+		return &Array{ElementType: Substitute(theType.ElementType, bindings)}
+	case *Tuple:
+		elementTypes := make([]Type, len(theType.ElementTypes))
+		for i, elementType := range theType.ElementTypes {
+			elementTypes[i] = Substitute(elementType, bindings)
+		}
+		return &Tuple{ElementTypes: elementTypes}
+	case *Map:
+		return &Map{KeyType: Substitute(theType.KeyType, bindings), ValueType: Substitute(theType.ValueType, bindings)}
+	case *Optional:
+		return NewOptional(Substitute(theType.Base, bindings))
+	case *Function:
+		typeParameters := make([]*TypeParameter, 0)
+		for _, typeParameter := range theType.TypeParameters {
+			if _, bound := bindings[typeParameter]; !bound {
+				typeParameters = append(typeParameters, typeParameter)
+			}
+		}
+		parameterTypes := make([]Type, len(theType.ParameterTypes))
+		for i, parameterType := range theType.ParameterTypes {
+			parameterTypes[i] = Substitute(parameterType, bindings)
+		}
+		return &Function{
+			TypeParameters: typeParameters,
+			ParameterTypes: parameterTypes,
+			ParameterNames: theType.ParameterNames,
+			DefaultCount:   theType.DefaultCount,
+			Variadic:       theType.Variadic,
+			ReturnType:     Substitute(theType.ReturnType, bindings),
+		}
+	case *Union:
+		members := make([]Type, len(theType.Types))
+		for i, member := range theType.Types {
+			members[i] = Substitute(member, bindings)
+		}
+		return NewUnion(members...)
+	case *Iface:
+		members := make(map[string]Type)
+		for name, memberType := range theType.Members {
+			members[name] = Substitute(memberType, bindings)
+		}
+		return &Iface{Members: members}
+	case *Struct:
+		fields := make(map[string]Type)
+		for name, fieldType := range theType.Fields {
+			fields[name] = Substitute(fieldType, bindings)
+		}
+		return &Struct{FieldNames: theType.FieldNames, Fields: fields}
+	case *Enum:
+		variants := make(map[string][]Type)
+		for name, payloadTypes := range theType.Variants {
+			variants[name] = make([]Type, len(payloadTypes))
+			for i, payloadType := range payloadTypes {
+				variants[name][i] = Substitute(payloadType, bindings)
+			}
+		}
+		typeArguments := make([]Type, len(theType.Root().TypeParameters))
+		for i, typeParameter := range theType.Root().TypeParameters {
+			if theType.TypeArguments != nil {
+				typeArguments[i] = Substitute(theType.TypeArguments[i], bindings)
+			} else {
+				typeArguments[i] = Substitute(typeParameter, bindings)
+			}
+		}
+		return &Enum{Name: theType.Name, VariantNames: theType.VariantNames, Variants: variants,
+			TypeArguments: typeArguments, Origin: theType.Root()}
+	}
+	return theType
+}
+
+func NewOptional(base Type) Type {
+	switch base.(type) {
+	case *Never, *Null, *Void, *Optional:
+		return base
+	default:
+		return &Optional{Base: base}
+	}
+}
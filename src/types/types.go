@@ -1,5 +1,7 @@
 package types
 
+import "reflect"
+
 const (
 	TypeNever  = "never"
 	TypeNull   = "null"
@@ -8,6 +10,7 @@ const (
 	TypeInt    = "int"
 	TypeFloat  = "float"
 	TypeBool   = "bool"
+	TypeMap    = "map"
 )
 
 type Type interface {
@@ -99,41 +102,103 @@ func (stringType *String) IsAssignable(other Type, _ *Context) bool {
 }
 
 type Function struct {
+	TypeParameters []*TypeParameter
 	ParameterTypes []Type
+	ParameterNames []string
+	DefaultCount   int
+	Variadic       bool
 	ReturnType     Type
 }
 
 func (functionType *Function) ToString() string {
-	result := "fn("
+	result := "fn"
+	if len(functionType.TypeParameters) > 0 {
+		result += "<"
+		for i, typeParameter := range functionType.TypeParameters {
+			if i > 0 {
+				result += ", "
+			}
+			result += typeParameter.ToString()
+		}
+		result += ">"
+	}
+	result += "("
 	for i, parameter := range functionType.ParameterTypes {
 		if i > 0 {
 			result += ", "
 		}
+		if i >= functionType.FixedCount() {
+			result += "..."
+		}
 		result += parameter.ToString()
+		if i >= functionType.RequiredCount() && i < functionType.FixedCount() {
+			result += " = ..."
+		}
 	}
 	return result + ") " + functionType.ReturnType.ToString()
 }
 
 func (functionType *Function) IsAssignable(other Type, context *Context) bool {
 	if other, isFunction := other.(*Function); isFunction {
-		if len(functionType.ParameterTypes) == len(other.ParameterTypes) {
-			for i := range functionType.ParameterTypes {
-				if !functionType.ParameterTypes[i].IsAssignable(other.ParameterTypes[i], context) {
-					return false
-				}
+		if !functionType.AcceptsParametersOf(other) {
+			return false
+		}
+		// other must accept every argument this function type accepts
+		for i := range functionType.ParameterTypes {
+			if !other.ParameterType(i).IsAssignable(functionType.ParameterTypes[i], context) {
+				return false
 			}
-			return true
 		}
+		return functionType.ReturnType.IsAssignable(other.ReturnType, context)
 	}
 	return false
 }
 
+// FixedCount returns the amount of parameters that are not variadic
+func (functionType *Function) FixedCount() int {
+	if functionType.Variadic {
+		return len(functionType.ParameterTypes) - 1
+	}
+	return len(functionType.ParameterTypes)
+}
+
+// RequiredCount returns the amount of parameters without default values that are not variadic
+func (functionType *Function) RequiredCount() int {
+	return functionType.FixedCount() - functionType.DefaultCount
+}
+
+// ParameterType returns the type of the parameter receiving the argument at the given position
+func (functionType *Function) ParameterType(index int) Type {
+	if index >= functionType.FixedCount() {
+		return functionType.ParameterTypes[len(functionType.ParameterTypes)-1]
+	}
+	return functionType.ParameterTypes[index]
+}
+
+// AcceptsParametersOf checks if every call of this function type is a valid call of other. other may declare
+// additional parameters as long as they have default values, and a variadic parameter may take the place of
+// fixed ones.
+func (functionType *Function) AcceptsParametersOf(other *Function) bool {
+	if functionType.RequiredCount() < other.RequiredCount() {
+		return false
+	}
+	if functionType.Variadic {
+		return other.Variadic && functionType.FixedCount() == other.FixedCount()
+	}
+	return other.Variadic || functionType.FixedCount() <= other.FixedCount()
+}
+
 type Optional struct {
 	Base Type
 }
 
 func (optional *Optional) ToString() string {
-	return optional.Base.ToString() + "?"
+	switch optional.Base.(type) {
+	case *Union, *Function:
+		return "(" + optional.Base.ToString() + ")?"
+	default:
+		return optional.Base.ToString() + "?"
+	}
 }
 
 func (optional *Optional) IsAssignable(other Type, context *Context) bool {
@@ -147,6 +212,124 @@ func (optional *Optional) IsAssignable(other Type, context *Context) bool {
 	}
 }
 
+type Union struct {
+	Types []Type
+}
+
+func (union *Union) ToString() string {
+	result := ""
+	for i, member := range union.Types {
+		if i > 0 {
+			result += " | "
+		}
+		result += member.ToString()
+	}
+	return result
+}
+
+func (union *Union) IsAssignable(other Type, context *Context) bool {
+	if other, isUnion := other.(*Union); isUnion {
+		for _, otherMember := range other.Types {
+			if !union.IsAssignable(otherMember, context) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, member := range union.Types {
+		if member.IsAssignable(other, context) {
+			return true
+		}
+	}
+	return false
+}
+
+func NewUnion(members ...Type) Type {
+	flattened := make([]Type, 0)
+	nullable := false
+	var add func(member Type)
+	add = func(member Type) {
+		switch member := member.(type) {
+		case *Never:
+		case *Null:
+			nullable = true
+		case *Optional:
+			nullable = true
+			add(member.Base)
+		case *Union:
+			for _, unionMember := range member.Types {
+				add(unionMember)
+			}
+		default:
+			for _, existing := range flattened {
+				if reflect.DeepEqual(existing, member) {
+					return
+				}
+			}
+			flattened = append(flattened, member)
+		}
+	}
+	for _, member := range members {
+		add(member)
+	}
+
+	var union Type
+	switch len(flattened) {
+	case 0:
+		if nullable {
+			return &Null{}
+		}
+		return &Never{}
+	case 1:
+		union = flattened[0]
+	default:
+		union = &Union{Types: flattened}
+	}
+	if nullable {
+		return NewOptional(union)
+	}
+	return union
+}
+
+func unionMembers(theType Type) []Type {
+	switch theType := theType.(type) {
+	case *Union:
+		return theType.Types
+	case *Optional:
+		return append(unionMembers(theType.Base), &Null{})
+	default:
+		return []Type{theType}
+	}
+}
+
+// Narrow returns the part of declared that passes the type test against target
+func Narrow(declared Type, target Type, context *Context) Type {
+	if target.IsAssignable(declared, context) {
+		return declared
+	}
+	kept := make([]Type, 0)
+	for _, member := range unionMembers(declared) {
+		if target.IsAssignable(member, context) {
+			kept = append(kept, member)
+		}
+	}
+	if len(kept) == 0 {
+		return target
+	}
+	return NewUnion(kept...)
+}
+
+// Exclude returns the part of declared that fails the type test against target
+func Exclude(declared Type, target Type, context *Context) Type {
+	kept := make([]Type, 0)
+	for _, member := range unionMembers(declared) {
+		if !target.IsAssignable(member, context) {
+			kept = append(kept, member)
+		}
+	}
+	return NewUnion(kept...)
+}
+
 type Iface struct {
 	Members map[string]Type
 }
@@ -159,6 +342,25 @@ func (iface *Iface) ToString() string {
 	return result + "}"
 }
 
+// Intersect merges the members of both ifaces, keeping the narrower type of shared members
+func Intersect(first *Iface, second *Iface, context *Context) (*Iface, string, bool) {
+	members := make(map[string]Type)
+	for name, memberType := range first.Members {
+		members[name] = memberType
+	}
+	for name, memberType := range second.Members {
+		existingType, exists := members[name]
+		switch {
+		case !exists || existingType.IsAssignable(memberType, context):
+			members[name] = memberType
+		case memberType.IsAssignable(existingType, context):
+		default:
+			return nil, name, false
+		}
+	}
+	return &Iface{Members: members}, "", true
+}
+
 func (iface *Iface) IsAssignable(other Type, context *Context) bool {
 	for name, memberType := range iface.Members {
 		actualType, _, ok := context.GetTypeMemberType(name, other)
@@ -168,3 +370,246 @@ func (iface *Iface) IsAssignable(other Type, context *Context) bool {
 	}
 	return true
 }
+
+type Struct struct {
+	FieldNames []string
+	Fields     map[string]Type
+}
+
+func (structType *Struct) ToString() string {
+	result := "struct { "
+	for _, name := range structType.FieldNames {
+		result += name + ": " + structType.Fields[name].ToString() + "; "
+	}
+	return result + "}"
+}
+
+func (structType *Struct) IsAssignable(other Type, context *Context) bool {
+	if other, isStruct := other.(*Struct); isStruct && len(structType.Fields) == len(other.Fields) {
+		for name, fieldType := range structType.Fields {
+			otherFieldType, ok := other.Fields[name]
+			// fields can be assigned, so they must match exactly
+			if !ok || !isEquivalent(fieldType, otherFieldType, context) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Error is the type of caught errors, its position fields are filled in where it is first thrown
+var Error = &Struct{
+	FieldNames: []string{"kind", "message", "file", "line", "col"},
+	Fields: map[string]Type{
+		"kind":    &String{},
+		"message": &String{},
+		"file":    &Optional{Base: &String{}},
+		"line":    &Optional{Base: &Int{}},
+		"col":     &Optional{Base: &Int{}},
+	},
+}
+
+type Enum struct {
+	Name           string
+	VariantNames   []string
+	Variants       map[string][]Type
+	TypeParameters []*TypeParameter // of a declared generic enum
+	TypeArguments  []Type           // of an instantiated generic enum
+	Origin         *Enum
+}
+
+func (enum *Enum) ToString() string {
+	if enum.Name != "" && len(enum.TypeArguments) > 0 {
+		result := enum.Name + "<"
+		for i, typeArgument := range enum.TypeArguments {
+			if i > 0 {
+				result += ", "
+			}
+			result += typeArgument.ToString()
+		}
+		return result + ">"
+	} else if enum.Name != "" {
+		return enum.Name
+	}
+	result := "enum { "
+	for _, name := range enum.VariantNames {
+		result += name
+		for i, payloadType := range enum.Variants[name] {
+			if i == 0 {
+				result += "("
+			} else {
+				result += ", "
+			}
+			result += payloadType.ToString()
+		}
+		if len(enum.Variants[name]) > 0 {
+			result += ")"
+		}
+		result += "; "
+	}
+	return result + "}"
+}
+
+func (enum *Enum) IsAssignable(other Type, context *Context) bool {
+	if other, isEnum := other.(*Enum); isEnum && enum.Root() == other.Root() {
+		for name, payloadTypes := range enum.Variants {
+			for i, payloadType := range payloadTypes {
+				if !payloadType.IsAssignable(other.Variants[name][i], context) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Root returns the declared enum an instantiated generic enum originates from
+func (enum *Enum) Root() *Enum {
+	if enum.Origin != nil {
+		return enum.Origin
+	}
+	return enum
+}
+
+type Array struct {
+	ElementType Type
+}
+
+func (array *Array) ToString() string {
+	return "[]" + elementString(array.ElementType)
+}
+
+func (array *Array) IsAssignable(other Type, context *Context) bool {
+	if other, isArray := other.(*Array); isArray {
+		// the empty array literal has no element type yet and fits every array
+		return isNever(other.ElementType) || isEquivalent(array.ElementType, other.ElementType, context)
+	}
+	return false
+}
+
+type Tuple struct {
+	ElementTypes []Type
+}
+
+func (tuple *Tuple) ToString() string {
+	result := "("
+	for i, elementType := range tuple.ElementTypes {
+		if i > 0 {
+			result += ", "
+		}
+		result += elementType.ToString()
+	}
+	return result + ")"
+}
+
+func (tuple *Tuple) IsAssignable(other Type, context *Context) bool {
+	if other, isTuple := other.(*Tuple); isTuple && len(tuple.ElementTypes) == len(other.ElementTypes) {
+		for i, elementType := range tuple.ElementTypes {
+			if !elementType.IsAssignable(other.ElementTypes[i], context) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+type Map struct {
+	KeyType   Type
+	ValueType Type
+}
+
+func (mapType *Map) ToString() string {
+	return "map[" + mapType.KeyType.ToString() + "]" + elementString(mapType.ValueType)
+}
+
+func (mapType *Map) IsAssignable(other Type, context *Context) bool {
+	if other, isMap := other.(*Map); isMap {
+		// the empty map literal has no key and value types yet and fits every map
+		if isNever(other.KeyType) && isNever(other.ValueType) {
+			return true
+		}
+		return isEquivalent(mapType.KeyType, other.KeyType, context) &&
+			isEquivalent(mapType.ValueType, other.ValueType, context)
+	}
+	return false
+}
+
+// elementString groups element types that would otherwise extend over the surrounding array or map type
+func elementString(elementType Type) string {
+	switch elementType.(type) {
+	case *Union, *Optional, *Function:
+		return "(" + elementType.ToString() + ")"
+	default:
+		return elementType.ToString()
+	}
+}
+
+func IsHashable(theType Type) bool {
+	switch theType.(type) {
+	case *Never, *String, *Int, *Float, *Bool:
+		return true
+	default:
+		return false
+	}
+}
+
+func CommonSupertype(first Type, second Type, context *Context) (Type, bool) {
+	switch {
+	case isNever(first):
+		return second, true
+	case isNever(second):
+		return first, true
+	case first.IsAssignable(second, context):
+		return first, true
+	case second.IsAssignable(first, context):
+		return second, true
+	}
+
+	firstBase, firstIsNullable := nullableBase(first)
+	secondBase, secondIsNullable := nullableBase(second)
+	if firstIsNullable || secondIsNullable {
+		if commonBase, ok := CommonSupertype(firstBase, secondBase, context); ok {
+			if _, isVoid := commonBase.(*Void); !isVoid {
+				return NewOptional(commonBase), true
+			}
+		}
+	}
+	return nil, false
+}
+
+func nullableBase(theType Type) (Type, bool) {
+	switch theType := theType.(type) {
+	case *Null:
+		return &Never{}, true
+	case *Optional:
+		return theType.Base, true
+	default:
+		return theType, false
+	}
+}
+
+type TypeParameter struct {
+	Name       string
+	Constraint Type
+}
+
+func (typeParameter *TypeParameter) ToString() string {
+	return typeParameter.Name
+}
+
+func (typeParameter *TypeParameter) IsAssignable(other Type, _ *Context) bool {
+	return typeParameter == other
+}
+
+// isEquivalent checks assignability in both directions, which is required for the contents of mutable types
+func isEquivalent(first Type, second Type, context *Context) bool {
+	return first.IsAssignable(second, context) && second.IsAssignable(first, context)
+}
+
+func isNever(theType Type) bool {
+	_, isNever := theType.(*Never)
+	return isNever
+}